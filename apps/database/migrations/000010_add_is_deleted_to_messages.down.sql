@@ -0,0 +1,3 @@
+-- 000010_add_is_deleted_to_messages.down.sql
+ALTER TABLE messages DROP COLUMN deleted_by;
+ALTER TABLE messages DROP COLUMN is_deleted;
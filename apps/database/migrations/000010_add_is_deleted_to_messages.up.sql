@@ -0,0 +1,4 @@
+-- 000010_add_is_deleted_to_messages.up.sql
+-- "Delete for everyone" keeps the row as a tombstone instead of removing it from history
+ALTER TABLE messages ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE messages ADD COLUMN deleted_by VARCHAR(32);
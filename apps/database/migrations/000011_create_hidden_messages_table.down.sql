@@ -0,0 +1 @@
+DROP TABLE IF EXISTS hidden_messages;
\ No newline at end of file
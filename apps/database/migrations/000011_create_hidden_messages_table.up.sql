@@ -0,0 +1,24 @@
+-- Table for "delete for me": hides a message only for one user
+-- The message itself stays visible for every other participant
+CREATE TABLE IF NOT EXISTS hidden_messages (
+    -- Unique ID for each hidden entry (hid_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Which message is hidden (FK to messages table)
+    message_id VARCHAR(32) NOT NULL,
+
+    -- For which user the message is hidden (FK to users table)
+    user_id VARCHAR(32) NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_hidden_message FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    CONSTRAINT fk_hidden_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- 1 message can only be hidden once per user
+    CONSTRAINT uq_hidden_message_user UNIQUE (message_id, user_id)
+);
+
+-- Index for fast query: "all messages hidden by user Y"
+CREATE INDEX idx_hidden_messages_user_id ON hidden_messages(user_id);
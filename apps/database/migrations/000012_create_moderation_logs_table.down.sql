@@ -0,0 +1 @@
+DROP TABLE IF EXISTS moderation_logs;
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- Table for recording moderation actions taken by group admins
+-- e.g. admin deleting another member's message
+CREATE TABLE IF NOT EXISTS moderation_logs (
+    -- Unique ID for each log entry (mod_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- In which conversation the action happened (FK to conversations table)
+    conversation_id VARCHAR(32) NOT NULL,
+
+    -- Who took the action (FK to users table)
+    actor_id VARCHAR(32) NOT NULL,
+
+    -- Whose content was moderated (FK to users table)
+    target_user_id VARCHAR(32) NOT NULL,
+
+    -- Which message was moderated (null for actions not tied to a message)
+    message_id VARCHAR(32),
+
+    -- What was done, e.g. 'delete_message'
+    action VARCHAR(30) NOT NULL,
+
+    -- Optional reason given by the admin
+    reason TEXT,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_modlog_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_modlog_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_modlog_target FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Index for fast query: "moderation log of conversation X, newest first"
+CREATE INDEX idx_moderation_logs_conversation_created ON moderation_logs(conversation_id, created_at DESC);
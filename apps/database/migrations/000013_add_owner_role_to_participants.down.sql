@@ -0,0 +1,3 @@
+-- 000013_add_owner_role_to_participants.down.sql
+UPDATE participants SET role = 'admin' WHERE role = 'owner';
+UPDATE participants SET role = 'member' WHERE role = 'moderator';
@@ -0,0 +1,30 @@
+-- 000013_add_owner_role_to_participants.up.sql
+-- Roles are now: owner, admin, moderator, member
+-- The creator of an existing group becomes its owner (if still a participant)
+UPDATE participants p
+SET role = 'owner'
+FROM conversations c
+WHERE p.conversation_id = c.id
+  AND c.type = 'group'
+  AND p.user_id = c.created_by;
+
+-- Groups whose creator already left: the highest-ranked participant becomes the owner
+-- (admin, then moderator, then member), the longest member on ties, like a successor when the owner leaves
+UPDATE participants p
+SET role = 'owner'
+FROM (
+    SELECT DISTINCT ON (candidate.conversation_id) candidate.id
+    FROM participants candidate
+    JOIN conversations c ON c.id = candidate.conversation_id
+    WHERE c.type = 'group'
+      AND NOT EXISTS (
+          SELECT 1 FROM participants existing
+          WHERE existing.conversation_id = candidate.conversation_id
+            AND existing.role = 'owner'
+      )
+    ORDER BY candidate.conversation_id,
+        CASE candidate.role WHEN 'admin' THEN 0 WHEN 'moderator' THEN 1 ELSE 2 END,
+        candidate.joined_at ASC,
+        candidate.id ASC
+) successor
+WHERE p.id = successor.id;
@@ -0,0 +1,2 @@
+-- 000014_add_payload_to_messages.down.sql
+ALTER TABLE messages DROP COLUMN payload;
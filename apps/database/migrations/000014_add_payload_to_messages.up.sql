@@ -0,0 +1,3 @@
+-- 000014_add_payload_to_messages.up.sql
+-- Structured payload for system messages (member added, kicked, group renamed, ...)
+ALTER TABLE messages ADD COLUMN payload JSONB;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS conversation_invites;
\ No newline at end of file
@@ -0,0 +1,38 @@
+-- Table for shareable group invite links
+CREATE TABLE IF NOT EXISTS conversation_invites (
+    -- Unique ID for each invite (inv_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Which group the invite is for (FK to conversations table)
+    conversation_id VARCHAR(32) NOT NULL,
+
+    -- Shareable code used in POST /invites/:code/join
+    code VARCHAR(32) NOT NULL UNIQUE,
+
+    -- Who created the invite (FK to users table)
+    created_by VARCHAR(32) NOT NULL,
+
+    -- When the invite stops working (null = never expires)
+    expires_at TIMESTAMP,
+
+    -- Maximum number of uses (null = unlimited)
+    max_uses INTEGER,
+
+    -- How many times the invite has been used
+    use_count INTEGER NOT NULL DEFAULT 0,
+
+    -- If true, joining creates a join request that an admin must approve
+    require_approval BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- When the invite was revoked (null = still active)
+    revoked_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_invite_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invite_creator FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Index for fast query: "all invites of conversation X"
+CREATE INDEX idx_conversation_invites_conversation_id ON conversation_invites(conversation_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS join_requests;
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- Table for join requests created through invites that require admin approval
+CREATE TABLE IF NOT EXISTS join_requests (
+    -- Unique ID for each request (jreq_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Which group the user wants to join (FK to conversations table)
+    conversation_id VARCHAR(32) NOT NULL,
+
+    -- Which invite was used (FK to conversation_invites table)
+    invite_id VARCHAR(32) NOT NULL,
+
+    -- Who wants to join (FK to users table)
+    user_id VARCHAR(32) NOT NULL,
+
+    -- Request status: 'pending', 'approved', or 'rejected'
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    -- Which admin approved/rejected the request (null = not reviewed yet)
+    reviewed_by VARCHAR(32),
+    reviewed_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_join_request_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_join_request_invite FOREIGN KEY (invite_id) REFERENCES conversation_invites(id) ON DELETE CASCADE,
+    CONSTRAINT fk_join_request_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 1 user can only have 1 pending request per conversation
+CREATE UNIQUE INDEX uq_join_requests_pending ON join_requests(conversation_id, user_id) WHERE status = 'pending';
+
+-- Index for fast query: "pending requests of conversation X"
+CREATE INDEX idx_join_requests_conversation_status ON join_requests(conversation_id, status);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS conversation_bans;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- Table for users banned from a group (they can't be added or join again)
+CREATE TABLE IF NOT EXISTS conversation_bans (
+    -- Unique ID for each ban (ban_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Which group the user is banned from (FK to conversations table)
+    conversation_id VARCHAR(32) NOT NULL,
+
+    -- Who is banned (FK to users table)
+    user_id VARCHAR(32) NOT NULL,
+
+    -- Who issued the ban (FK to users table)
+    banned_by VARCHAR(32) NOT NULL,
+
+    -- Optional reason given by the moderator
+    reason TEXT,
+
+    -- When the ban ends (null = permanent)
+    expires_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_ban_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ban_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ban_banned_by FOREIGN KEY (banned_by) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- 1 user can only have 1 ban per conversation (banning again updates it)
+    CONSTRAINT uq_ban_conversation_user UNIQUE (conversation_id, user_id)
+);
@@ -0,0 +1,24 @@
+-- Table for users blocking other users
+-- A block stops DMs in both directions and hides presence/typing between the two users
+CREATE TABLE IF NOT EXISTS user_blocks (
+    -- Unique ID for each block (blk_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Who blocked (FK to users table)
+    blocker_id VARCHAR(32) NOT NULL,
+
+    -- Who is blocked (FK to users table)
+    blocked_id VARCHAR(32) NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_block_blocker FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_block_blocked FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- 1 user can only block another user once
+    CONSTRAINT uq_block_blocker_blocked UNIQUE (blocker_id, blocked_id)
+);
+
+-- Index for fast query: "who blocked user Y"
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks(blocked_id);
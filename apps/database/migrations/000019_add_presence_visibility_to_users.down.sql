@@ -0,0 +1,2 @@
+-- 000019_add_presence_visibility_to_users.down.sql
+ALTER TABLE users DROP COLUMN presence_visibility;
\ No newline at end of file
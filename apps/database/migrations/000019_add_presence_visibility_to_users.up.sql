@@ -0,0 +1,3 @@
+-- 000019_add_presence_visibility_to_users.up.sql
+-- Who can see a user's is_online and last_seen: everyone, contacts or nobody
+ALTER TABLE users ADD COLUMN presence_visibility VARCHAR(20) NOT NULL DEFAULT 'everyone';
@@ -0,0 +1,6 @@
+-- 000020_add_rich_presence_to_users.down.sql
+ALTER TABLE users DROP COLUMN status_expires_at;
+ALTER TABLE users DROP COLUMN status_emoji;
+ALTER TABLE users DROP COLUMN status_text;
+ALTER TABLE users DROP COLUMN do_not_disturb;
+ALTER TABLE users DROP COLUMN presence_status;
\ No newline at end of file
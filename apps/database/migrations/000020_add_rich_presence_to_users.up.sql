@@ -0,0 +1,9 @@
+-- 000020_add_rich_presence_to_users.up.sql
+-- Presence state (online, idle, dnd, offline) and custom status with an optional expiry
+ALTER TABLE users ADD COLUMN presence_status VARCHAR(20) NOT NULL DEFAULT 'offline';
+ALTER TABLE users ADD COLUMN do_not_disturb BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN status_text VARCHAR(140);
+ALTER TABLE users ADD COLUMN status_emoji VARCHAR(32);
+ALTER TABLE users ADD COLUMN status_expires_at TIMESTAMP;
+
+UPDATE users SET presence_status = 'online' WHERE is_online = TRUE;
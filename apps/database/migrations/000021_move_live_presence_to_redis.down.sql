@@ -0,0 +1,3 @@
+-- 000021_move_live_presence_to_redis.down.sql
+ALTER TABLE users ADD COLUMN is_online BOOLEAN DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN presence_status VARCHAR(20) NOT NULL DEFAULT 'offline';
\ No newline at end of file
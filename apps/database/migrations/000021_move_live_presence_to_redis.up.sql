@@ -0,0 +1,5 @@
+-- 000021_move_live_presence_to_redis.up.sql
+-- Live presence (online/idle/dnd) now lives in Redis with heartbeat TTLs,
+-- Postgres only keeps last_seen (and the user's DND/custom status settings)
+ALTER TABLE users DROP COLUMN is_online;
+ALTER TABLE users DROP COLUMN presence_status;
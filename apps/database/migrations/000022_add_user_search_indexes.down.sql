@@ -0,0 +1,3 @@
+-- 000022_add_user_search_indexes.down.sql
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
\ No newline at end of file
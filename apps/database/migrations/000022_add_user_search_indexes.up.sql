@@ -0,0 +1,6 @@
+-- 000022_add_user_search_indexes.up.sql
+-- Trigram indexes for the user directory search (prefix + fuzzy match on name and email)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING GIN (LOWER(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (LOWER(email) gin_trgm_ops);
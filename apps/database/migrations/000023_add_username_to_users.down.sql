@@ -0,0 +1,5 @@
+-- 000023_add_username_to_users.down.sql
+DROP INDEX IF EXISTS idx_users_username_trgm;
+DROP INDEX IF EXISTS idx_users_username_lower;
+ALTER TABLE users DROP COLUMN username_changed_at;
+ALTER TABLE users DROP COLUMN username;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- 000023_add_username_to_users.up.sql
+-- Unique, case-insensitive handle (used for lookups and @mentions)
+ALTER TABLE users ADD COLUMN username VARCHAR(32);
+ALTER TABLE users ADD COLUMN username_changed_at TIMESTAMP;
+
+CREATE UNIQUE INDEX idx_users_username_lower ON users (LOWER(username));
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (LOWER(username) gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS message_mentions;
+ALTER TABLE messages DROP COLUMN mentions;
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- Mention entities of a message (type, user, offset and length in characters), returned with the message
+ALTER TABLE messages ADD COLUMN mentions JSONB;
+
+-- Table for users mentioned in a message (@username, @user_<id>, @all, @here)
+-- @all and @here are resolved to one row per mentioned participant
+CREATE TABLE IF NOT EXISTS message_mentions (
+    -- Unique ID for each mention (mnt_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Which message mentions the user (FK to messages table)
+    message_id VARCHAR(32) NOT NULL,
+
+    -- Conversation of the message (FK to conversations table), to filter by membership
+    conversation_id VARCHAR(32) NOT NULL,
+
+    -- Who is mentioned (FK to users table)
+    user_id VARCHAR(32) NOT NULL,
+
+    -- How the user was mentioned: 'user', 'all' or 'here'
+    type VARCHAR(10) NOT NULL DEFAULT 'user',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_mention_message FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mention_conversation FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mention_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- 1 message mentions a user only once
+    CONSTRAINT uq_mention_message_user UNIQUE (message_id, user_id)
+);
+
+-- Index for fast query: "where was user Y mentioned" (newest first)
+CREATE INDEX idx_message_mentions_user_id ON message_mentions(user_id, created_at DESC);
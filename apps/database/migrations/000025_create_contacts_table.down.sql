@@ -0,0 +1,3 @@
+ALTER TABLE participants DROP COLUMN is_message_request;
+ALTER TABLE users DROP COLUMN dm_contacts_only;
+DROP TABLE IF EXISTS contacts;
\ No newline at end of file
@@ -0,0 +1,35 @@
+-- Table for contacts (friend requests): pending until the addressee accepts, then a contact of both users
+CREATE TABLE IF NOT EXISTS contacts (
+    -- Unique ID for each contact (cnt_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Who sent the request (FK to users table)
+    requester_id VARCHAR(32) NOT NULL,
+
+    -- Who received the request (FK to users table)
+    addressee_id VARCHAR(32) NOT NULL,
+
+    -- Contact status: 'pending' or 'accepted' (declined and removed contacts are deleted)
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- When the request was accepted (null = still pending)
+    accepted_at TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_contact_requester FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_addressee FOREIGN KEY (addressee_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 2 users have only 1 contact row, whoever sent the request
+CREATE UNIQUE INDEX uq_contacts_pair ON contacts (LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+
+-- Index for fast query: "requests received by user Y"
+CREATE INDEX idx_contacts_addressee_id ON contacts(addressee_id);
+
+-- Only contacts can DM the user (DMs from others go to the message request inbox)
+ALTER TABLE users ADD COLUMN dm_contacts_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- DM waiting in the participant's message request inbox (hidden from their conversation list)
+ALTER TABLE participants ADD COLUMN is_message_request BOOLEAN NOT NULL DEFAULT FALSE;
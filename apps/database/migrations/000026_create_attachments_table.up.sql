@@ -0,0 +1,42 @@
+-- Table for uploaded files: one row per upload, linked to a message once it is sent
+CREATE TABLE IF NOT EXISTS attachments (
+    -- Unique ID for each attachment (att_xxx), also the media ID of GET /media/:id
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Who uploaded the file (FK to users table)
+    uploader_id VARCHAR(32) NOT NULL,
+
+    -- Message the file was sent with (FK to messages table), null until sent
+    message_id VARCHAR(32),
+
+    -- Order of the attachment in its message
+    position INT NOT NULL DEFAULT 0,
+
+    -- Key of the file in the object store (e.g. 'images/att_xxx.jpg')
+    object_key VARCHAR(255) NOT NULL,
+
+    -- Original file name, detected MIME type and size in bytes
+    filename VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+
+    -- Pixel dimensions (images and videos) and duration in milliseconds (audio and video), when known
+    width INT,
+    height INT,
+    duration_ms INT,
+
+    -- Hex SHA-256 of the file content
+    sha256 CHAR(64) NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_attachment_uploader FOREIGN KEY (uploader_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachment_message FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL
+);
+
+-- Index for fast query: "attachments of message X"
+CREATE INDEX idx_attachments_message_id ON attachments(message_id, position);
+
+-- Index for fast query: "uploads of user Y"
+CREATE INDEX idx_attachments_uploader_id ON attachments(uploader_id, created_at DESC);
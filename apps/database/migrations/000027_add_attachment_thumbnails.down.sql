@@ -0,0 +1,2 @@
+ALTER TABLE attachments DROP COLUMN blurhash;
+ALTER TABLE attachments DROP COLUMN thumbnails;
\ No newline at end of file
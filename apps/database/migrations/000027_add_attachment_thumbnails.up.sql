@@ -0,0 +1,6 @@
+-- Resized JPEG copies of image attachments: [{"name":"small","width":320,"height":240}, ...]
+-- Stored under 'thumbnails/<attachment id>_<name>.jpg'
+ALTER TABLE attachments ADD COLUMN thumbnails JSONB;
+
+-- BlurHash placeholder of image attachments, shown while the image loads
+ALTER TABLE attachments ADD COLUMN blurhash VARCHAR(64);
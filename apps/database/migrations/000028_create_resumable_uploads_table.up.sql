@@ -0,0 +1,40 @@
+-- Table for resumable (tus) uploads in progress: the received bytes are kept on disk until the upload is complete
+CREATE TABLE IF NOT EXISTS resumable_uploads (
+    -- Unique ID for each upload (rup_xxx), part of its tus URL
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Who is uploading (FK to users table)
+    uploader_id VARCHAR(32) NOT NULL,
+
+    -- Original file name and total size in bytes (Upload-Length)
+    filename VARCHAR(255) NOT NULL,
+    length BIGINT NOT NULL,
+
+    -- Number of bytes received so far (Upload-Offset)
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+
+    -- Options of POST /upload sent in Upload-Metadata
+    duration_ms INT,
+    keep_metadata BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Attachment created once the upload is complete (FK to attachments table)
+    attachment_id VARCHAR(32),
+
+    -- When the upload is abandoned and its partial file removed (pushed back by every chunk)
+    expires_at TIMESTAMP NOT NULL,
+
+    -- Lease of the request writing the upload (receiving a chunk, cancelling it), one at a time across API instances.
+    -- Renewed while the request runs, free once locked_until has passed
+    lock_token VARCHAR(32),
+    locked_until TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_resumable_upload_uploader FOREIGN KEY (uploader_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_resumable_upload_attachment FOREIGN KEY (attachment_id) REFERENCES attachments(id) ON DELETE SET NULL
+);
+
+-- Index for the cleanup job: "uploads expired before now"
+CREATE INDEX idx_resumable_uploads_expires_at ON resumable_uploads(expires_at);
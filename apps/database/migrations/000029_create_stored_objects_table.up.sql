@@ -0,0 +1,28 @@
+-- Table for files in the object store: attachments with the same content share one object, counted by ref_count
+CREATE TABLE IF NOT EXISTS stored_objects (
+    -- Key of the file in the object store, derived from its content (e.g. 'images/<sha256>.jpg')
+    object_key VARCHAR(255) PRIMARY KEY,
+
+    -- Hex SHA-256 of the file content
+    sha256 CHAR(64) NOT NULL,
+
+    -- Detected MIME type and size in bytes
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+
+    -- Number of attachments using the object, it is deleted when the last one goes away
+    ref_count INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing uploads: one object per attachment, stored under the attachment ID
+INSERT INTO stored_objects (object_key, sha256, mime_type, size, ref_count, created_at, updated_at)
+SELECT object_key, MIN(sha256), MIN(mime_type), MAX(size), COUNT(*), MIN(created_at), MAX(created_at)
+FROM attachments
+GROUP BY object_key
+ON CONFLICT (object_key) DO NOTHING;
+
+-- Index for fast query: "objects no attachment uses anymore"
+CREATE INDEX idx_stored_objects_ref_count ON stored_objects(ref_count) WHERE ref_count = 0;
@@ -0,0 +1,4 @@
+ALTER TABLE attachments
+    DROP COLUMN IF EXISTS scan_status,
+    DROP COLUMN IF EXISTS scanned_by,
+    DROP COLUMN IF EXISTS scanned_at;
\ No newline at end of file
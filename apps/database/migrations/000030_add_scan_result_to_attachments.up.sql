@@ -0,0 +1,5 @@
+-- Malware scan result of each attachment: status, which scanner and when (uploads before scanning stay 'unscanned')
+ALTER TABLE attachments
+    ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'unscanned',
+    ADD COLUMN IF NOT EXISTS scanned_by VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE conversations DROP COLUMN IF EXISTS avatar_attachment_id;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_attachment_id;
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- Profile and group pictures uploaded through /upload reference their attachment: these references,
+-- not the avatar URL, let other users download the picture
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_attachment_id VARCHAR(32);
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS avatar_attachment_id VARCHAR(32);
+
+ALTER TABLE users
+    ADD CONSTRAINT fk_users_avatar_attachment FOREIGN KEY (avatar_attachment_id) REFERENCES attachments(id) ON DELETE SET NULL;
+ALTER TABLE conversations
+    ADD CONSTRAINT fk_conversations_avatar_attachment FOREIGN KEY (avatar_attachment_id) REFERENCES attachments(id) ON DELETE SET NULL;
+
+-- Existing pictures: a profile keeps its uploaded picture if the user uploaded it themself,
+-- a group if one of its participants did
+UPDATE users u
+SET avatar_attachment_id = a.id
+FROM attachments a
+WHERE a.id = SUBSTRING(u.avatar_url FROM '/media/([A-Za-z0-9_]+)$')
+  AND a.uploader_id = u.id;
+
+UPDATE conversations c
+SET avatar_attachment_id = a.id
+FROM attachments a
+WHERE a.id = SUBSTRING(c.avatar_url FROM '/media/([A-Za-z0-9_]+)$')
+  AND EXISTS (
+      SELECT 1 FROM participants p
+      WHERE p.conversation_id = c.id
+        AND p.user_id = a.uploader_id
+  );
+
+-- Index for fast query: "is this attachment a profile/group picture"
+CREATE INDEX idx_users_avatar_attachment_id ON users(avatar_attachment_id) WHERE avatar_attachment_id IS NOT NULL;
+CREATE INDEX idx_conversations_avatar_attachment_id ON conversations(avatar_attachment_id) WHERE avatar_attachment_id IS NOT NULL;
@@ -0,0 +1,20 @@
+-- Table for uploads checked against the uploader's storage quota and hourly upload limit whose attachment isn't
+-- recorded yet (file being scanned or stored). They count towards both limits, so concurrent uploads can't exceed them together
+CREATE TABLE IF NOT EXISTS upload_reservations (
+    -- Unique ID for each reservation (urs_xxx)
+    id VARCHAR(32) PRIMARY KEY,
+
+    -- Who is uploading (FK to users table)
+    user_id VARCHAR(32) NOT NULL,
+
+    -- Size in bytes of the file being uploaded
+    size BIGINT NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_upload_reservation_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Index for fast query: "reservations of this user"
+CREATE INDEX idx_upload_reservations_user_id ON upload_reservations(user_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE stored_objects
+    DROP COLUMN IF EXISTS ready,
+    DROP COLUMN IF EXISTS thumbnails;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- Whether the file was written to the object store: an object is referenced before its file is stored, so an upload
+-- only reuses a ready one and stores the file itself otherwise. Thumbnails stored with the file, copied to the
+-- attachments reusing it
+ALTER TABLE stored_objects
+    ADD COLUMN IF NOT EXISTS ready BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS thumbnails JSONB;
+
+-- Existing objects were stored by their first attachment, with its thumbnails
+UPDATE stored_objects
+SET ready = TRUE,
+    thumbnails = (
+        SELECT attachments.thumbnails
+        FROM attachments
+        WHERE attachments.object_key = stored_objects.object_key
+        ORDER BY attachments.created_at, attachments.id
+        LIMIT 1
+    );
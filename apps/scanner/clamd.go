@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks streamed to clamd
+const clamdChunkSize = 64 * 1024
+
+// clamdScanner scans files with a ClamAV daemon over TCP, using the INSTREAM command
+type clamdScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner creates a Scanner backed by the clamd listening on address (e.g. "localhost:3310").
+// clamd rejects streams over its StreamMaxLength (25 MB by default): raise it to the maximum upload size
+func NewClamdScanner(address string, timeout time.Duration) Scanner {
+	return &clamdScanner{address: address, timeout: timeout}
+}
+
+// Name implements Scanner
+func (scanner *clamdScanner) Name() string {
+	return "clamd"
+}
+
+// Thorough implements Scanner
+func (scanner *clamdScanner) Thorough() bool {
+	return true
+}
+
+// Scan streams the content to clamd and reads its verdict
+func (scanner *clamdScanner) Scan(ctx context.Context, content io.Reader) (*ScanResult, error) {
+	// 1. Connect, the whole scan must end before the timeout
+	dialer := net.Dialer{Timeout: scanner.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", scanner.address)
+	if err != nil {
+		return nil, fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(scanner.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	// 2. Stream the content: "zINSTREAM\0", then chunks prefixed by their big-endian length, a zero length ends it
+	if err := streamToClamd(conn, content); err != nil {
+		// clamd may have closed the connection with an error reply (e.g. size limit exceeded)
+		if reply, replyErr := readClamdReply(conn); replyErr == nil && reply != "" {
+			return nil, fmt.Errorf("clamd: %s", reply)
+		}
+		return nil, fmt.Errorf("clamd: %w", err)
+	}
+
+	// 3. Verdict: "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return nil, fmt.Errorf("clamd: %w", err)
+	}
+	return parseClamdReply(reply)
+}
+
+// streamToClamd sends the INSTREAM command and the content
+func streamToClamd(conn net.Conn, content io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := content.Read(chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk, uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// readClamdReply reads a null-terminated reply
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// parseClamdReply turns clamd's reply into a result
+func parseClamdReply(reply string) (*ScanResult, error) {
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return &ScanResult{Infected: true, Signature: signature}, nil
+	case reply == "stream: OK":
+		return &ScanResult{}, nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return nil, fmt.Errorf("clamd: %s", strings.TrimSuffix(reply, " ERROR"))
+	default:
+		return nil, fmt.Errorf("clamd: unexpected reply %q", reply)
+	}
+}
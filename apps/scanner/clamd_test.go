@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		name string
+		reply string
+		wantInfected bool
+		wantSignature string
+		wantErr bool
+	}{
+		{name: "clean", reply: "stream: OK"},
+		{name: "infected", reply: "stream: Eicar-Test-Signature FOUND", wantInfected: true, wantSignature: "Eicar-Test-Signature"},
+		{name: "signature with spaces", reply: "stream: Win.Test.EICAR_HDB-1 (variant) FOUND", wantInfected: true, wantSignature: "Win.Test.EICAR_HDB-1 (variant)"},
+		{name: "size limit exceeded", reply: "INSTREAM size limit exceeded. ERROR", wantErr: true},
+		{name: "scan error", reply: "stream: Can't allocate memory ERROR", wantErr: true},
+		{name: "empty reply", reply: "", wantErr: true},
+		{name: "unexpected reply", reply: "PONG", wantErr: true},
+		{name: "OK of another stream", reply: "file: OK", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseClamdReply(tt.reply)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseClamdReply(%q) = %+v, want an error", tt.reply, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseClamdReply(%q) returned error: %v", tt.reply, err)
+			}
+
+			if result.Infected != tt.wantInfected || result.Signature != tt.wantSignature {
+				t.Errorf("parseClamdReply(%q) = %+v, want infected %t with signature %q",
+					tt.reply, result, tt.wantInfected, tt.wantSignature)
+			}
+		})
+	}
+}
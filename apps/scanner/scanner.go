@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"chatapp-api/config"
+	"context"
+	"io"
+	"log"
+	"time"
+)
+
+// ScanResult is the verdict of a malware scan
+type ScanResult struct {
+	Infected bool
+	// Name of the detected malware (e.g. "Eicar-Test-Signature"), empty when clean
+	Signature string
+}
+
+// Scanner checks uploaded files for malware before they are stored
+type Scanner interface {
+	// Name identifies the scanner in the scan results recorded on attachments (e.g. "clamd")
+	Name() string
+
+	// Thorough reports whether a clean result means the file was really checked for malware.
+	// Files passing a scanner that isn't (the test scanner) are recorded as unscanned
+	Thorough() bool
+
+	// Scan reads the whole content and reports whether it is infected.
+	// An error means the content couldn't be scanned (e.g. scanner unreachable), not that it is infected
+	Scan(ctx context.Context, content io.Reader) (*ScanResult, error)
+}
+
+// NewScanner creates the scanner selected by config.Scanner.Driver
+func NewScanner(config *config.Config) Scanner {
+	switch config.Scanner.Driver {
+	case "clamd":
+		log.Printf("Malware scanner: clamd (%s)", config.Scanner.ClamdAddress)
+		return NewClamdScanner(config.Scanner.ClamdAddress, time.Duration(config.Scanner.TimeoutSecs)*time.Second)
+
+	case "test":
+		if config.App.Env != "development" {
+			log.Fatalf("The test scanner only detects the EICAR test file, set CLAMD_ADDRESS to scan uploads with ClamAV (APP_ENV=%s)", config.App.Env)
+		}
+		log.Printf("Malware scanner: test (only detects the EICAR test file, set CLAMD_ADDRESS to use ClamAV)")
+		return NewTestScanner()
+
+	default:
+		log.Fatalf("Unknown scanner driver %q (use clamd or test)", config.Scanner.Driver)
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// eicarTestString is the EICAR antivirus test file: harmless, but reported as malware by every scanner
+const eicarTestString = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// eicarSignature is the name ClamAV gives to the EICAR test file
+const eicarSignature = "Eicar-Test-Signature"
+
+// testScanner detects nothing but the EICAR test string, to exercise malware rejection without ClamAV
+type testScanner struct{}
+
+// NewTestScanner creates a Scanner that only flags content containing the EICAR test string
+func NewTestScanner() Scanner {
+	return &testScanner{}
+}
+
+// Name implements Scanner
+func (scanner *testScanner) Name() string {
+	return "test"
+}
+
+// Thorough implements Scanner, nothing but the EICAR test string is detected
+func (scanner *testScanner) Thorough() bool {
+	return false
+}
+
+// Scan looks for the EICAR test string anywhere in the content, reading it by chunks
+func (scanner *testScanner) Scan(ctx context.Context, content io.Reader) (*ScanResult, error) {
+	signature := []byte(eicarTestString)
+	buffer := make([]byte, 64*1024)
+	carried := 0 // Bytes kept from the previous chunk, the string may span two chunks
+
+	for {
+		n, err := content.Read(buffer[carried:])
+		window := buffer[:carried+n]
+		if bytes.Contains(window, signature) {
+			return &ScanResult{Infected: true, Signature: eicarSignature}, nil
+		}
+		if err == io.EOF {
+			return &ScanResult{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		carried = min(len(window), len(signature)-1)
+		copy(buffer, window[len(window)-carried:])
+	}
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTestScanner(t *testing.T) {
+	// Larger than the scanner's 64KB read buffer, to place the EICAR string across a chunk boundary
+	padding := strings.Repeat("a", 64*1024-10)
+
+	tests := []struct {
+		name string
+		content io.Reader
+		wantInfected bool
+		wantErr bool
+	}{
+		{name: "empty", content: strings.NewReader("")},
+		{name: "clean", content: strings.NewReader("hello world")},
+		{name: "EICAR file", content: strings.NewReader(eicarTestString), wantInfected: true},
+		{name: "EICAR inside other content", content: strings.NewReader("header " + eicarTestString + " footer"), wantInfected: true},
+		{name: "EICAR across two chunks", content: strings.NewReader(padding + eicarTestString), wantInfected: true},
+		{name: "EICAR read one byte at a time", content: iotest.OneByteReader(strings.NewReader(eicarTestString)), wantInfected: true},
+		{name: "EICAR after a large clean part", content: io.MultiReader(bytes.NewReader(make([]byte, 200*1024)), strings.NewReader(eicarTestString)), wantInfected: true},
+		{name: "truncated EICAR", content: strings.NewReader(eicarTestString[:len(eicarTestString)-1])},
+		{name: "read error", content: iotest.ErrReader(errors.New("disk error")), wantErr: true},
+	}
+
+	scanner := NewTestScanner()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scanner.Scan(context.Background(), tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan() = %+v, want an error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan() returned error: %v", err)
+			}
+
+			if result.Infected != tt.wantInfected {
+				t.Errorf("Scan() infected = %t, want %t", result.Infected, tt.wantInfected)
+			}
+			if tt.wantInfected && result.Signature != eicarSignature {
+				t.Errorf("Scan() signature = %q, want %q", result.Signature, eicarSignature)
+			}
+		})
+	}
+
+	if scanner.Thorough() {
+		t.Error("the test scanner must not report clean files as scanned")
+	}
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"chatapp-api/config"
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localStore stores objects as files under a directory on the local disk
+type localStore struct {
+	rootDir    string
+	baseURL    string
+	signingKey string
+}
+
+// NewLocalStore creates an ObjectStore backed by the local disk.
+// Files are served by ServeLocalFiles under baseURL, signed with signingKey
+func NewLocalStore(rootDir string, baseURL string, signingKey string) (ObjectStore, error) {
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &localStore{rootDir: rootDir, baseURL: strings.TrimRight(baseURL, "/"), signingKey: signingKey}, nil
+}
+
+// Put writes the object to a temporary file, then renames it so readers never see a partial file
+func (store *localStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	// 1. Create the parent folder
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// 2. Write to a temporary file in the same folder
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// 3. Move it into place
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the object file
+func (store *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return file, err
+}
+
+// Delete removes the object file
+func (store *localStore) Delete(ctx context.Context, key string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL returns the URL of the file under the local file-serving route, with an expiry and an HMAC signature
+func (store *localStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {localSignature(store.signingKey, key, expires)},
+	}
+	return store.baseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// path maps a key to a file path inside the root folder
+func (store *localStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(store.rootDir, filepath.FromSlash(key)), nil
+}
+
+// ServeLocalFiles handles GET /files/*key for the local store: it checks the signature and expiry
+// of a URL made by SignedURL, then serves the file (Range requests included)
+func ServeLocalFiles(config *config.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := strings.TrimPrefix(ctx.Param("key"), "/")
+		expires := ctx.Query("expires")
+		signature := ctx.Query("signature")
+
+		// 1. Check the signature
+		expected := localSignature(config.Storage.SigningKey, key, expires)
+		if !validKey(key) || !hmac.Equal([]byte(signature), []byte(expected)) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		// 2. Check the expiry
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > expiresAt {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		// 3. Serve the file
+		path := filepath.Join(config.Storage.LocalDir, filepath.FromSlash(key))
+		if _, err := os.Stat(path); err != nil {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.Header("Cache-Control", "private, max-age="+strconv.Itoa(config.Storage.SignedURLExpiryMins*60))
+		ctx.File(path)
+	}
+}
+
+// localSignature signs a key and its expiry (unix seconds) for local download URLs
+func localSignature(signingKey string, key string, expires string) string {
+	return hex.EncodeToString(hmacSHA256([]byte(signingKey), key+"\n"+expires))
+}
@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"chatapp-api/config"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsignedPayload tells S3 the body isn't part of the signature, so uploads can be streamed
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Store stores objects in an S3-compatible bucket (AWS S3, MinIO, ...), signing requests with AWS Signature V4
+type s3Store struct {
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	accessKey    string
+	secretKey    string
+	usePathStyle bool
+	// Endpoint used in signed URLs, the one clients can reach
+	publicEndpoint *url.URL
+	client         *http.Client
+}
+
+// NewS3Store creates an ObjectStore backed by an S3-compatible bucket
+func NewS3Store(config config.StorageConfig) ObjectStore {
+	endpoint, err := url.Parse(strings.TrimRight(config.S3Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{Scheme: "https", Host: "s3." + config.S3Region + ".amazonaws.com"}
+	}
+
+	publicEndpoint, err := url.Parse(strings.TrimRight(config.S3PublicEndpoint, "/"))
+	if err != nil || publicEndpoint.Host == "" {
+		publicEndpoint = endpoint
+	}
+
+	return &s3Store{
+		endpoint:       endpoint,
+		region:         config.S3Region,
+		bucket:         config.S3Bucket,
+		accessKey:      config.S3AccessKey,
+		secretKey:      config.S3SecretKey,
+		usePathStyle:   config.S3UsePathStyle,
+		publicEndpoint: publicEndpoint,
+		client:         &http.Client{},
+	}
+}
+
+// Put uploads the object with a single PUT request
+func (store *s3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := store.newRequest(ctx, http.MethodPut, key, body, func(req *http.Request) {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", contentType)
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError("upload", response)
+	}
+	return nil
+}
+
+// Get downloads the object
+func (store *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := store.newRequest(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, responseError("download", response)
+	}
+	return response.Body, nil
+}
+
+// Delete removes the object (S3 answers 204 even if it doesn't exist)
+func (store *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := store.newRequest(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return responseError("delete", response)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL (Signature V4 in the query string). S3 caps the expiry at 7 days
+func (store *s3Store) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	objectURL := store.objectURL(store.publicEndpoint, key)
+	amzDate := time.Now().UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + store.region + "/s3/aws4_request"
+
+	// 1. Signing parameters, only the host header is signed
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {store.accessKey + "/" + scope},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.Itoa(int(expiry.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+
+	// 2. Canonical request and signature
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + objectURL.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	objectURL.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + store.signature(amzDate, scope, canonicalRequest)
+	return objectURL.String(), nil
+}
+
+// objectURL returns the URL of an object on an endpoint, in path style (endpoint/bucket/key)
+// or virtual-hosted style (bucket.endpoint/key)
+func (store *s3Store) objectURL(endpoint *url.URL, key string) *url.URL {
+	objectURL := *endpoint
+	if store.usePathStyle {
+		objectURL.Path = "/" + store.bucket + "/" + key
+		objectURL.RawPath = "/" + encodePath(store.bucket) + "/" + encodePath(key)
+	} else {
+		objectURL.Host = store.bucket + "." + endpoint.Host
+		objectURL.Path = "/" + key
+		objectURL.RawPath = "/" + encodePath(key)
+	}
+	return &objectURL
+}
+
+// newRequest builds a signed request for an object. prepare sets headers that must be signed (e.g. Content-Type)
+func (store *s3Store) newRequest(ctx context.Context, method string, key string, body io.Reader, prepare func(req *http.Request)) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid object key %q", key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, store.objectURL(store.endpoint, key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if prepare != nil {
+		prepare(req)
+	}
+
+	store.sign(req, time.Now())
+	return req, nil
+}
+
+// sign adds the AWS Signature V4 Authorization header to a request
+func (store *s3Store) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + store.region + "/s3/aws4_request"
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+
+	// 1. Canonical headers: host plus every header we set, lowercased and sorted
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// 2. Canonical request
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	// 3. Signature
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		store.accessKey, scope, signedHeaders, store.signature(amzDate, scope, canonicalRequest)))
+}
+
+// signature signs a canonical request with the key derived for its date
+func (store *s3Store) signature(amzDate string, scope string, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex(canonicalRequest)
+	return hex.EncodeToString(hmacSHA256(store.signingKey(amzDate[:8]), stringToSign))
+}
+
+// signingKey derives the AWS Signature V4 key for a date (YYYYMMDD)
+func (store *s3Store) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+store.secretKey), date)
+	key = hmacSHA256(key, store.region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalQuery encodes query parameters sorted by name, as required by Signature V4
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, encodeURIComponent(name)+"="+encodeURIComponent(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// encodePath URI-encodes every segment of a key, keeping the "/" separators
+func encodePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = encodeURIComponent(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeURIComponent encodes everything but unreserved characters (A-Z a-z 0-9 - _ . ~), as S3 expects
+func encodeURIComponent(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+// hmacSHA256 computes HMAC-SHA256(key, data)
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// hashHex returns the hex-encoded SHA-256 of a string
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"chatapp-api/config"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrObjectNotFound is returned when the requested object doesn't exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore stores uploaded files under a key such as "images/img_01HXYZ.jpg"
+type ObjectStore interface {
+	// Put stores an object, replacing any object with the same key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get opens an object for reading (ErrObjectNotFound if it doesn't exist)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes an object (no error if it doesn't exist)
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL that allows downloading an object until it expires.
+	// Objects are private, this is the only way for clients to fetch them
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// NewObjectStore creates the object store selected by config.Storage.Driver
+func NewObjectStore(config *config.Config) ObjectStore {
+	switch config.Storage.Driver {
+	case "local":
+		if config.Storage.SigningKey == "" {
+			log.Fatalf("Storage: STORAGE_SIGNING_KEY is required for the local driver outside development (APP_ENV=%s)", config.App.Env)
+		}
+		if config.Storage.SigningKey == config.JWT.Secret || config.Storage.SigningKey == config.JWT.RefreshSecret {
+			log.Fatalf("Storage: STORAGE_SIGNING_KEY must differ from the JWT secrets")
+		}
+		store, err := NewLocalStore(config.Storage.LocalDir, config.Storage.LocalBaseURL, config.Storage.SigningKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize local storage: %v", err)
+		}
+		log.Printf("Storage: local disk (%s)", config.Storage.LocalDir)
+		return store
+
+	case "s3":
+		if config.Storage.S3AccessKey == "" || config.Storage.S3SecretKey == "" {
+			log.Fatalf("Storage: S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 driver")
+		}
+		log.Printf("Storage: S3 (%s, bucket %s)", config.Storage.S3Endpoint, config.Storage.S3Bucket)
+		return NewS3Store(config.Storage)
+
+	case "supabase":
+		if config.Supabase.URL == "" || config.Supabase.Key == "" {
+			log.Fatalf("Storage: SUPABASE_URL and SUPABASE_KEY are required for the supabase driver")
+		}
+		log.Printf("Storage: Supabase (bucket %s)", config.Supabase.Bucket)
+		return NewSupabaseStore(config.Supabase)
+
+	default:
+		log.Fatalf("Unknown storage driver %q (use local, s3 or supabase)", config.Storage.Driver)
+		return nil
+	}
+}
+
+// validKey reports whether a key is a safe relative object path (no "..", no leading "/")
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
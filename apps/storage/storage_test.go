@@ -0,0 +1,31 @@
+package storage
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "images/abc.jpg", want: true},
+		{key: "thumbnails/abc_small.jpg", want: true},
+		{key: "file.pdf", want: true},
+		{key: "a/b/c/d.txt", want: true},
+		{key: "images/..abc.jpg", want: true},
+		{key: "", want: false},
+		{key: "/etc/passwd", want: false},
+		{key: "../secret", want: false},
+		{key: "images/../../secret", want: false},
+		{key: "images/./abc.jpg", want: false},
+		{key: ".", want: false},
+		{key: "images//abc.jpg", want: false},
+		{key: "images/", want: false},
+		{key: `images\..\secret`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := validKey(tt.key); got != tt.want {
+			t.Errorf("validKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"bytes"
+	"chatapp-api/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// supabaseStore stores objects in a Supabase Storage bucket through its REST API
+type supabaseStore struct {
+	baseURL string
+	key     string
+	bucket  string
+	client  *http.Client
+}
+
+// NewSupabaseStore creates an ObjectStore backed by Supabase Storage
+func NewSupabaseStore(config config.SupabaseConfig) ObjectStore {
+	return &supabaseStore{
+		baseURL: strings.TrimRight(config.URL, "/"),
+		key:     config.Key,
+		bucket:  config.Bucket,
+		client:  &http.Client{},
+	}
+}
+
+// Put uploads the object (x-upsert replaces an existing object with the same key)
+func (store *supabaseStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := store.newRequest(ctx, http.MethodPost, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return responseError("upload", response)
+	}
+	return nil
+}
+
+// Get downloads the object
+func (store *supabaseStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := store.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Supabase answers 400 with a "not_found" error for missing objects
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusBadRequest {
+		response.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, responseError("download", response)
+	}
+	return response.Body, nil
+}
+
+// Delete removes the object
+func (store *supabaseStore) Delete(ctx context.Context, key string) error {
+	req, err := store.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound && response.StatusCode != http.StatusBadRequest {
+		return responseError("delete", response)
+	}
+	return nil
+}
+
+// SignedURL asks Supabase for a signed download URL
+func (store *supabaseStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	// 1. Request a signed URL for the object
+	body, err := json.Marshal(map[string]int{"expiresIn": int(expiry.Seconds())})
+	if err != nil {
+		return "", err
+	}
+
+	signURL := fmt.Sprintf("%s/storage/v1/object/sign/%s/%s", store.baseURL, store.bucket, key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, signURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+store.key)
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := store.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", responseError("sign", response)
+	}
+
+	// 2. The answer is relative to the storage API: {"signedURL": "/object/sign/<bucket>/<key>?token=..."}
+	var result struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return store.baseURL + "/storage/v1" + result.SignedURL, nil
+}
+
+// newRequest builds an authenticated request for an object
+func (store *supabaseStore) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid object key %q", key)
+	}
+
+	objectURL := fmt.Sprintf("%s/storage/v1/object/%s/%s", store.baseURL, store.bucket, key)
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+store.key)
+	return req, nil
+}
+
+// responseError builds an error from an unexpected storage response
+func responseError(operation string, response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("storage %s failed (status %d): %s", operation, response.StatusCode, strings.TrimSpace(string(body)))
+}
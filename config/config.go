@@ -15,6 +15,8 @@ type Config struct {
 	Redis    RedisConfig
 	JWT      JWTConfig
 	Supabase SupabaseConfig
+	Storage  StorageConfig
+	Scanner  ScannerConfig
 }
 
 // AppConfig
@@ -56,6 +58,64 @@ type SupabaseConfig struct {
 	Bucket string
 }
 
+// StorageConfig for the object store used by uploads
+type StorageConfig struct {
+	// Driver: "local", "s3" or "supabase"
+	Driver string
+
+	// How long signed download URLs stay valid
+	SignedURLExpiryMins int
+
+	// Local disk (Driver = "local"). Files are served by the /files route, LocalBaseURL is its public URL.
+	// SigningKey signs its download URLs: a dedicated key, required outside development (never the JWT secret)
+	LocalDir     string
+	LocalBaseURL string
+	SigningKey   string
+
+	// S3-compatible storage such as AWS S3 or MinIO (Driver = "s3").
+	// S3PublicEndpoint is the endpoint clients reach the bucket through, used in signed URLs (defaults to S3Endpoint)
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3UsePathStyle   bool
+	S3PublicEndpoint string
+
+	// Resumable (tus) uploads: partial files are kept under ResumableDir until complete,
+	// abandoned ones are removed ResumableExpiryHours after their last chunk.
+	// With several API instances ResumableDir must be a volume they all share, the chunks of an upload can reach any of them
+	ResumableDir         string
+	ResumableMaxSizeMB   int
+	ResumableExpiryHours int
+
+	// Per-user limits: total size of a user's attachments and number of uploads per rolling hour (0 = unlimited)
+	QuotaMB        int
+	UploadsPerHour int
+
+	// Garbage collection of orphaned uploads (never sent, or sent with a deleted message): runs every
+	// GCIntervalMins in the server (0 = only with the "gc" command), removes those older than GCGraceHours.
+	// GCDryRun only logs what would be removed
+	GCIntervalMins int
+	GCGraceHours   int
+	GCDryRun       bool
+}
+
+// ScannerConfig for the malware scanning of uploads
+type ScannerConfig struct {
+	// Driver: "clamd" (ClamAV daemon) or "test" (only detects the EICAR test file, development only)
+	Driver string
+
+	// TCP address of clamd (Driver = "clamd"), e.g. "localhost:3310"
+	ClamdAddress string
+
+	// How long a scan may take
+	TimeoutSecs int
+
+	// FailOpen accepts uploads unscanned when the scanner is unavailable (rejected by default)
+	FailOpen bool
+}
+
 // LoadConfig to read .env dan return Config struct
 func LoadConfig() *Config {
 	// Load .env file
@@ -76,6 +136,80 @@ func LoadConfig() *Config {
 		refreshExpiry = 30
 	}
 
+	// Parse signed download URL expiry (minutes)
+	signedURLExpiry, err := strconv.Atoi(getEnv("STORAGE_SIGNED_URL_EXPIRY_MINUTES", "15"))
+	if err != nil {
+		signedURLExpiry = 15
+	}
+
+	// Default storage driver: Supabase when it is configured, local disk otherwise
+	defaultDriver := "local"
+	if getEnv("SUPABASE_URL", "") != "" {
+		defaultDriver = "supabase"
+	}
+
+	// Parse resumable upload limits
+	resumableMaxSize, err := strconv.Atoi(getEnv("STORAGE_RESUMABLE_MAX_SIZE_MB", "1024"))
+	if err != nil {
+		resumableMaxSize = 1024
+	}
+	resumableExpiry, err := strconv.Atoi(getEnv("STORAGE_RESUMABLE_EXPIRY_HOURS", "24"))
+	if err != nil {
+		resumableExpiry = 24
+	}
+
+	// Parse per-user upload limits
+	quota, err := strconv.Atoi(getEnv("STORAGE_QUOTA_MB", "2048"))
+	if err != nil {
+		quota = 2048
+	}
+	uploadsPerHour, err := strconv.Atoi(getEnv("STORAGE_UPLOADS_PER_HOUR", "100"))
+	if err != nil {
+		uploadsPerHour = 100
+	}
+
+	// Parse orphaned upload garbage collection settings
+	gcInterval, err := strconv.Atoi(getEnv("STORAGE_GC_INTERVAL_MINUTES", "60"))
+	if err != nil {
+		gcInterval = 60
+	}
+	gcGrace, err := strconv.Atoi(getEnv("STORAGE_GC_GRACE_HOURS", "24"))
+	if err != nil {
+		gcGrace = 24
+	}
+	gcDryRun, err := strconv.ParseBool(getEnv("STORAGE_GC_DRY_RUN", "false"))
+	if err != nil {
+		gcDryRun = false
+	}
+
+	// Default scanner driver: clamd when it is configured, the EICAR test scanner otherwise (refused outside development)
+	defaultScanner := "test"
+	if getEnv("CLAMD_ADDRESS", "") != "" {
+		defaultScanner = "clamd"
+	}
+
+	// Parse malware scanner settings
+	scannerTimeout, err := strconv.Atoi(getEnv("SCANNER_TIMEOUT_SECONDS", "60"))
+	if err != nil {
+		scannerTimeout = 60
+	}
+	scannerFailOpen, err := strconv.ParseBool(getEnv("SCANNER_FAIL_OPEN", "false"))
+	if err != nil {
+		scannerFailOpen = false
+	}
+
+	// Parse S3 path-style addressing (needed by MinIO)
+	usePathStyle, err := strconv.ParseBool(getEnv("S3_USE_PATH_STYLE", "true"))
+	if err != nil {
+		usePathStyle = true
+	}
+
+	// Download URL signing key: only development falls back to a built-in key
+	signingKey := getEnv("STORAGE_SIGNING_KEY", "")
+	if signingKey == "" && getEnv("APP_ENV", "development") == "development" {
+		signingKey = "development_storage_signing_key"
+	}
+
 	return &Config{
 		App: AppConfig{
 			Name: getEnv("APP_NAME", "ChatApp API"),
@@ -106,6 +240,34 @@ func LoadConfig() *Config {
 			Key:    getEnv("SUPABASE_KEY", ""),
 			Bucket: getEnv("SUPABASE_BUCKET", "chat-media"),
 		},
+		Storage: StorageConfig{
+			Driver:               getEnv("STORAGE_DRIVER", defaultDriver),
+			SignedURLExpiryMins:  signedURLExpiry,
+			LocalDir:             getEnv("STORAGE_LOCAL_DIR", "./storage"),
+			LocalBaseURL:         getEnv("STORAGE_LOCAL_BASE_URL", "http://localhost:"+getEnv("APP_PORT", "8080")+"/files"),
+			SigningKey:           signingKey,
+			S3Endpoint:           getEnv("S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:             getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:             getEnv("S3_BUCKET", "chat-media"),
+			S3AccessKey:          getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:          getEnv("S3_SECRET_KEY", ""),
+			S3UsePathStyle:       usePathStyle,
+			S3PublicEndpoint:     getEnv("S3_PUBLIC_ENDPOINT", ""),
+			ResumableDir:         getEnv("STORAGE_RESUMABLE_DIR", "./tmp/resumable"),
+			ResumableMaxSizeMB:   resumableMaxSize,
+			ResumableExpiryHours: resumableExpiry,
+			QuotaMB:              quota,
+			UploadsPerHour:       uploadsPerHour,
+			GCIntervalMins:       gcInterval,
+			GCGraceHours:         gcGrace,
+			GCDryRun:             gcDryRun,
+		},
+		Scanner: ScannerConfig{
+			Driver:       getEnv("SCANNER_DRIVER", defaultScanner),
+			ClamdAddress: getEnv("CLAMD_ADDRESS", "localhost:3310"),
+			TimeoutSecs:  scannerTimeout,
+			FailOpen:     scannerFailOpen,
+		},
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"chatapp-api/models/web"
 	authService "chatapp-api/services/auth"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -125,16 +126,24 @@ func (controller *authControllerImpl) GetMe(ctx *gin.Context) {
 	}
 
 	// 3. Return user response
+	user.ClearExpiredStatus(time.Now())
 	ctx.JSON(http.StatusOK, web.ApiResponse{
 		Success: true,
 		Message: "User profile retrieved",
 		Data: web.UserResponse{
 			ID: user.ID,
 			Name: user.Name,
+			Username: user.Username,
 			Email: user.Email,
 			AvatarURL: user.AvatarURL,
 			IsOnline: user.IsOnline,
 			LastSeen: user.LastSeen,
+			PresenceVisibility: user.PresenceVisibility,
+			DMContactsOnly: user.DMContactsOnly,
+			Status: user.PresenceStatus,
+			StatusText: user.StatusText,
+			StatusEmoji: user.StatusEmoji,
+			StatusExpiresAt: user.StatusExpiresAt,
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
 		},
@@ -0,0 +1,24 @@
+package contact
+
+import "github.com/gin-gonic/gin"
+
+// ContactController interface for contact HTTP handlers
+type ContactController interface {
+	// GetContacts handles GET /contacts
+	GetContacts(ctx *gin.Context)
+
+	// RemoveContact handles DELETE /contacts/:userId
+	RemoveContact(ctx *gin.Context)
+
+	// SendRequest handles POST /contacts/requests
+	SendRequest(ctx *gin.Context)
+
+	// GetRequests handles GET /contacts/requests?direction=incoming|outgoing
+	GetRequests(ctx *gin.Context)
+
+	// AcceptRequest handles POST /contacts/requests/:id/accept
+	AcceptRequest(ctx *gin.Context)
+
+	// DeclineRequest handles POST /contacts/requests/:id/decline
+	DeclineRequest(ctx *gin.Context)
+}
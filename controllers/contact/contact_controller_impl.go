@@ -0,0 +1,188 @@
+package contact
+
+import (
+	"chatapp-api/middleware"
+	"chatapp-api/models/web"
+	contactService "chatapp-api/services/contact"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contactControllerImpl implements ContactController interface
+type contactControllerImpl struct {
+	contactService contactService.ContactService
+}
+
+// NewContactController creates a new instance of ContactController
+func NewContactController(contactService contactService.ContactService) ContactController {
+	return &contactControllerImpl{contactService: contactService}
+}
+
+// GetContacts handles GET /contacts
+func (controller *contactControllerImpl) GetContacts(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Parse pagination (page & limit)
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	// 3. Call service to get contacts
+	result, pagination, err := controller.contactService.GetContacts(ctx.Request.Context(), userID, page, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.PaginatedResponse{
+		Success: true,
+		Message: "Contacts fetched successfully",
+		Data: result,
+		Pagination: *pagination,
+	})
+}
+
+// RemoveContact handles DELETE /contacts/:userId
+func (controller *contactControllerImpl) RemoveContact(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to remove contact
+	if err := controller.contactService.RemoveContact(ctx.Request.Context(), userID, ctx.Param("userId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Contact removed successfully",
+	})
+}
+
+// SendRequest handles POST /contacts/requests
+func (controller *contactControllerImpl) SendRequest(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind request body
+	var req web.SendContactRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Call service to send request
+	result, err := controller.contactService.SendRequest(ctx.Request.Context(), userID, req.UserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusCreated, web.ApiResponse{
+		Success: true,
+		Message: "Contact request sent",
+		Data: result,
+	})
+}
+
+// GetRequests handles GET /contacts/requests?direction=incoming|outgoing
+func (controller *contactControllerImpl) GetRequests(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind query
+	var req web.GetContactRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Call service to get requests
+	result, err := controller.contactService.GetRequests(ctx.Request.Context(), userID, req.Direction)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Contact requests fetched successfully",
+		Data: result,
+	})
+}
+
+// AcceptRequest handles POST /contacts/requests/:id/accept
+func (controller *contactControllerImpl) AcceptRequest(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to accept request
+	result, err := controller.contactService.AcceptRequest(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Contact request accepted",
+		Data: result,
+	})
+}
+
+// DeclineRequest handles POST /contacts/requests/:id/decline
+func (controller *contactControllerImpl) DeclineRequest(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to decline (or cancel) request
+	if err := controller.contactService.DeclineRequest(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Contact request declined",
+	})
+}
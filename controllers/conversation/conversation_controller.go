@@ -24,4 +24,31 @@ type ConversationController interface {
 	
 	// KickParticipant handles DELETE /conversations/:id/participants/:userId
 	KickParticipant(ctx *gin.Context)
+
+	// UpdateParticipantRole handles PUT /conversations/:id/participants/:userId/role
+	UpdateParticipantRole(ctx *gin.Context)
+
+	// TransferOwnership handles POST /conversations/:id/transfer-ownership
+	TransferOwnership(ctx *gin.Context)
+
+	// BanParticipant handles POST /conversations/:id/bans
+	BanParticipant(ctx *gin.Context)
+
+	// UnbanParticipant handles DELETE /conversations/:id/bans/:userId
+	UnbanParticipant(ctx *gin.Context)
+
+	// GetBans handles GET /conversations/:id/bans
+	GetBans(ctx *gin.Context)
+
+	// GetModerationLog handles GET /conversations/:id/moderation-log
+	GetModerationLog(ctx *gin.Context)
+
+	// GetMessageRequests handles GET /message-requests
+	GetMessageRequests(ctx *gin.Context)
+
+	// AcceptMessageRequest handles POST /message-requests/:id/accept
+	AcceptMessageRequest(ctx *gin.Context)
+
+	// DeclineMessageRequest handles POST /message-requests/:id/decline
+	DeclineMessageRequest(ctx *gin.Context)
 }
\ No newline at end of file
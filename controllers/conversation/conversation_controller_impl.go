@@ -5,6 +5,7 @@ import (
 	"chatapp-api/models/web"
 	convService "chatapp-api/services/conversation"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -228,4 +229,274 @@ func (controller *conversationControllerImpl) KickParticipant(ctx *gin.Context)
 		Success: true,
 		Message: "Participant kicked successfully",
 	})
-}
\ No newline at end of file
+}
+
+// UpdateParticipantRole handles PUT /conversations/:id/participants/:userId/role
+func (controller *conversationControllerImpl) UpdateParticipantRole(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID and target user ID from param
+	conversationID := ctx.Param("id")
+	targetUserID := ctx.Param("userId")
+
+	// 3. Bind request body
+	var req web.UpdateParticipantRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid body request",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 4. Call service to update role
+	result, err := controller.convService.UpdateParticipantRole(ctx.Request.Context(), userID, conversationID, targetUserID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Participant role updated successfully",
+		Data: result,
+	})
+}
+
+// TransferOwnership handles POST /conversations/:id/transfer-ownership
+func (controller *conversationControllerImpl) TransferOwnership(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID from param
+	conversationID := ctx.Param("id")
+
+	// 3. Bind request body
+	var req web.TransferOwnershipRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid body request",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 4. Call service to transfer ownership
+	result, err := controller.convService.TransferOwnership(ctx.Request.Context(), userID, conversationID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Ownership transferred successfully",
+		Data: result,
+	})
+}
+
+// BanParticipant handles POST /conversations/:id/bans
+func (controller *conversationControllerImpl) BanParticipant(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID from param
+	conversationID := ctx.Param("id")
+
+	// 3. Bind request body
+	var req web.BanParticipantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid body request",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 4. Call service to ban user
+	err = controller.convService.BanParticipant(ctx.Request.Context(), userID, conversationID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User banned successfully",
+	})
+}
+
+// UnbanParticipant handles DELETE /conversations/:id/bans/:userId
+func (controller *conversationControllerImpl) UnbanParticipant(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID and target user ID from param
+	conversationID := ctx.Param("id")
+	targetUserID := ctx.Param("userId")
+
+	// 3. Call service to unban user
+	err = controller.convService.UnbanParticipant(ctx.Request.Context(), userID, conversationID, targetUserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User unbanned successfully",
+	})
+}
+
+// GetBans handles GET /conversations/:id/bans
+func (controller *conversationControllerImpl) GetBans(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID from param
+	conversationID := ctx.Param("id")
+
+	// 3. Call service to get bans
+	result, err := controller.convService.GetBans(ctx.Request.Context(), userID, conversationID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Bans fetched successfully",
+		Data: result,
+	})
+}
+
+// GetModerationLog handles GET /conversations/:id/moderation-log
+func (controller *conversationControllerImpl) GetModerationLog(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID from param
+	conversationID := ctx.Param("id")
+
+	// 3. Parse pagination query (page & limit)
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	// 4. Call service to get moderation log
+	result, pagination, err := controller.convService.GetModerationLog(ctx.Request.Context(), userID, conversationID, page, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return response
+	ctx.JSON(http.StatusOK, web.PaginatedResponse{
+		Success: true,
+		Message: "Moderation log fetched successfully",
+		Data: result,
+		Pagination: *pagination,
+	})
+}
+
+// GetMessageRequests handles GET /message-requests
+func (controller *conversationControllerImpl) GetMessageRequests(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get message requests
+	result, err := controller.convService.GetMessageRequests(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Message requests fetched successfully",
+		Data: result,
+	})
+}
+
+// AcceptMessageRequest handles POST /message-requests/:id/accept
+func (controller *conversationControllerImpl) AcceptMessageRequest(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to accept the message request
+	result, err := controller.convService.AcceptMessageRequest(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Message request accepted",
+		Data: result,
+	})
+}
+
+// DeclineMessageRequest handles POST /message-requests/:id/decline
+func (controller *conversationControllerImpl) DeclineMessageRequest(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to decline the message request
+	if err := controller.convService.DeclineMessageRequest(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Message request declined",
+	})
+}
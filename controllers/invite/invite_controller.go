@@ -0,0 +1,27 @@
+package invite
+
+import "github.com/gin-gonic/gin"
+
+// InviteController interface for invite link and join request HTTP handlers
+type InviteController interface {
+	// CreateInvite handles POST /conversations/:id/invites
+	CreateInvite(ctx *gin.Context)
+
+	// GetInvites handles GET /conversations/:id/invites
+	GetInvites(ctx *gin.Context)
+
+	// RevokeInvite handles DELETE /conversations/:id/invites/:inviteId
+	RevokeInvite(ctx *gin.Context)
+
+	// JoinByCode handles POST /invites/:code/join
+	JoinByCode(ctx *gin.Context)
+
+	// GetJoinRequests handles GET /conversations/:id/join-requests
+	GetJoinRequests(ctx *gin.Context)
+
+	// ApproveJoinRequest handles POST /conversations/:id/join-requests/:requestId/approve
+	ApproveJoinRequest(ctx *gin.Context)
+
+	// RejectJoinRequest handles POST /conversations/:id/join-requests/:requestId/reject
+	RejectJoinRequest(ctx *gin.Context)
+}
@@ -0,0 +1,196 @@
+package invite
+
+import (
+	"chatapp-api/middleware"
+	"chatapp-api/models/web"
+	inviteService "chatapp-api/services/invite"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inviteControllerImpl implements InviteController interface
+type inviteControllerImpl struct {
+	inviteService inviteService.InviteService
+}
+
+// NewInviteController creates a new instance of InviteController
+func NewInviteController(inviteService inviteService.InviteService) InviteController {
+	return &inviteControllerImpl{inviteService: inviteService}
+}
+
+// CreateInvite handles POST /conversations/:id/invites
+func (controller *inviteControllerImpl) CreateInvite(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Get conversation ID from param
+	conversationID := ctx.Param("id")
+
+	// 3. Bind request body
+	var req web.CreateInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 4. Call service to create invite
+	result, err := controller.inviteService.CreateInvite(ctx.Request.Context(), userID, conversationID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return response
+	ctx.JSON(http.StatusCreated, web.ApiResponse{
+		Success: true,
+		Message: "Invite created successfully",
+		Data: result,
+	})
+}
+
+// GetInvites handles GET /conversations/:id/invites
+func (controller *inviteControllerImpl) GetInvites(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get invites
+	result, err := controller.inviteService.GetInvites(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Invites fetched successfully",
+		Data: result,
+	})
+}
+
+// RevokeInvite handles DELETE /conversations/:id/invites/:inviteId
+func (controller *inviteControllerImpl) RevokeInvite(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to revoke invite
+	err = controller.inviteService.RevokeInvite(ctx.Request.Context(), userID, ctx.Param("id"), ctx.Param("inviteId"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Invite revoked successfully",
+	})
+}
+
+// JoinByCode handles POST /invites/:code/join
+func (controller *inviteControllerImpl) JoinByCode(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to join
+	result, err := controller.inviteService.JoinByCode(ctx.Request.Context(), userID, ctx.Param("code"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response (202 when waiting for admin approval)
+	if result.Status == inviteService.JoinStatusPending {
+		ctx.JSON(http.StatusAccepted, web.ApiResponse{
+			Success: true,
+			Message: "Join request sent, waiting for admin approval",
+			Data: result,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Joined conversation successfully",
+		Data: result,
+	})
+}
+
+// GetJoinRequests handles GET /conversations/:id/join-requests
+func (controller *inviteControllerImpl) GetJoinRequests(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get pending join requests
+	result, err := controller.inviteService.GetJoinRequests(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Join requests fetched successfully",
+		Data: result,
+	})
+}
+
+// ApproveJoinRequest handles POST /conversations/:id/join-requests/:requestId/approve
+func (controller *inviteControllerImpl) ApproveJoinRequest(ctx *gin.Context) {
+	controller.reviewJoinRequest(ctx, true, "Join request approved")
+}
+
+// RejectJoinRequest handles POST /conversations/:id/join-requests/:requestId/reject
+func (controller *inviteControllerImpl) RejectJoinRequest(ctx *gin.Context) {
+	controller.reviewJoinRequest(ctx, false, "Join request rejected")
+}
+
+// reviewJoinRequest is the shared handler for approving/rejecting a join request
+func (controller *inviteControllerImpl) reviewJoinRequest(ctx *gin.Context, approve bool, message string) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to review the request
+	result, err := controller.inviteService.ReviewJoinRequest(ctx.Request.Context(), userID, ctx.Param("id"), ctx.Param("requestId"), approve)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: message,
+		Data: result,
+	})
+}
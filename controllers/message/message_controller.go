@@ -16,9 +16,12 @@ type MessageController interface {
 	// UpdateMessage PUT /messages/:messageId
 	UpdateMessage(ctx *gin.Context)
 
-	// DeleteMessage DELETE /messages/:messageId
+	// DeleteMessage DELETE /messages/:messageId?scope=me|everyone
 	DeleteMessage(ctx *gin.Context)
 
 	// GetMessageReceipts GET /messages/:messageId/receipts
 	GetMessageReceipts(ctx *gin.Context)
+
+	// GetMentions GET /mentions
+	GetMentions(ctx *gin.Context)
 }
\ No newline at end of file
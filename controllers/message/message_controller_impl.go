@@ -186,13 +186,23 @@ func (controller *messageControllerImpl) DeleteMessage(ctx *gin.Context) {
 	}
 	// 2. Get message ID from URL parameter
 	messageID := ctx.Param("messageId")
-	// 3. Call service (soft delete)
-	err = controller.messageService.DeleteMessage(ctx.Request.Context(), userID, messageID)
+	// 3. Bind query (?scope=me|everyone)
+	var req web.DeleteMessageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid scope. Allowed: me, everyone",
+			Error: err.Error(),
+		})
+		return
+	}
+	// 4. Call service
+	err = controller.messageService.DeleteMessage(ctx.Request.Context(), userID, messageID, &req)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
-	// 4. Return response
+	// 5. Return response
 	ctx.JSON(http.StatusOK, web.ApiResponse{
 		Success: true,
 		Message: "Message deleted successfully",
@@ -225,3 +235,32 @@ func (controller *messageControllerImpl) GetMessageReceipts(ctx *gin.Context) {
 		Data:    receipts,
 	})
 }
+
+// GetMentions handles GET /mentions
+func (controller *messageControllerImpl) GetMentions(ctx *gin.Context) {
+	// 1. Get userID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Parse pagination (page & limit)
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	// 3. Call service to get mentions
+	mentions, pagination, err := controller.messageService.GetMentions(ctx.Request.Context(), userID, page, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.PaginatedResponse{
+		Success: true,
+		Message: "Mentions fetched successfully",
+		Data: mentions,
+		Pagination: *pagination,
+	})
+}
@@ -6,4 +6,28 @@ import "github.com/gin-gonic/gin"
 type UploadController interface {
 	// UploadFile handles POST /api/v1/upload
 	UploadFile(ctx *gin.Context)
+
+	// GetMedia handles GET /api/v1/media/:id
+	GetMedia(ctx *gin.Context)
+
+	// GetStorageUsage handles GET /api/v1/auth/me/storage
+	GetStorageUsage(ctx *gin.Context)
+
+	// ResumableOptions handles OPTIONS /api/v1/upload/resumable (tus discovery)
+	ResumableOptions(ctx *gin.Context)
+
+	// CreateResumableUpload handles POST /api/v1/upload/resumable (tus creation)
+	CreateResumableUpload(ctx *gin.Context)
+
+	// HeadResumableUpload handles HEAD /api/v1/upload/resumable/:id (tus offset)
+	HeadResumableUpload(ctx *gin.Context)
+
+	// PatchResumableUpload handles PATCH /api/v1/upload/resumable/:id (tus chunk)
+	PatchResumableUpload(ctx *gin.Context)
+
+	// DeleteResumableUpload handles DELETE /api/v1/upload/resumable/:id (tus termination)
+	DeleteResumableUpload(ctx *gin.Context)
+
+	// GetResumableUpload handles GET /api/v1/upload/resumable/:id
+	GetResumableUpload(ctx *gin.Context)
 }
\ No newline at end of file
@@ -1,9 +1,11 @@
 package upload
 
 import (
+	"chatapp-api/middleware"
 	"chatapp-api/models/web"
 	uploadService "chatapp-api/services/upload"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,7 +22,25 @@ func NewUploadController(uploadService uploadService.UploadService) UploadContro
 
 // UploadFile handles POST /api/v1/upload
 func (controller *uploadControllerImpl) UploadFile(ctx *gin.Context) {
-	// 1. Get file from multipart form
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind optional form fields
+	var req web.UploadFileRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Get file from multipart form
 	file, header, err := ctx.Request.FormFile("file")
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
@@ -32,17 +52,244 @@ func (controller *uploadControllerImpl) UploadFile(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	// 2. Call upload service
-	result, err := controller.uploadService.UploadFile(ctx.Request.Context(), file, header)
+	// 4. Call upload service
+	result, err := controller.uploadService.UploadFile(ctx.Request.Context(), userID, file, header, &req)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	// 3. Return success response
+	// 5. Return success response
 	ctx.JSON(http.StatusOK, web.ApiResponse{
 		Success: true,
 		Message: "File uploaded successfully",
 		Data: result,
 	})
+}
+
+// GetMedia handles GET /api/v1/media/:id
+// Returns a short-lived signed URL, or redirects to it with ?redirect=true
+func (controller *uploadControllerImpl) GetMedia(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind query parameters (thumbnail, redirect)
+	var req web.GetMediaRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Call upload service (checks access and signs the URL)
+	result, err := controller.uploadService.GetMedia(ctx.Request.Context(), userID, ctx.Param("id"), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Redirect or return the signed URL
+	if req.Redirect {
+		ctx.Header("Cache-Control", "no-store")
+		ctx.Redirect(http.StatusFound, result.URL)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Media URL created successfully",
+		Data: result,
+	})
+}
+
+// GetStorageUsage handles GET /api/v1/auth/me/storage
+func (controller *uploadControllerImpl) GetStorageUsage(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call upload service
+	result, err := controller.uploadService.GetStorageUsage(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Storage usage retrieved successfully",
+		Data: result,
+	})
+}
+
+// ResumableOptions handles OPTIONS /api/v1/upload/resumable
+// The tus discovery headers are set by the tus middleware
+func (controller *uploadControllerImpl) ResumableOptions(ctx *gin.Context) {
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateResumableUpload handles POST /api/v1/upload/resumable
+// Upload-Length is the file size, Upload-Metadata carries filename, duration_ms and keep_metadata (base64 values)
+func (controller *uploadControllerImpl) CreateResumableUpload(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind tus headers
+	var req web.CreateResumableUploadRequest
+	if err := ctx.ShouldBindHeader(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid upload headers",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Call upload service
+	result, err := controller.uploadService.CreateResumableUpload(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return the upload URL the chunks are sent to
+	ctx.Header("Location", "/api/v1/upload/resumable/"+result.ID)
+	ctx.Header("Upload-Expires", result.ExpiresAt.UTC().Format(http.TimeFormat))
+	ctx.JSON(http.StatusCreated, web.ApiResponse{
+		Success: true,
+		Message: "Upload created successfully",
+		Data: result,
+	})
+}
+
+// HeadResumableUpload handles HEAD /api/v1/upload/resumable/:id
+// Returns the offset to resume from in Upload-Offset
+func (controller *uploadControllerImpl) HeadResumableUpload(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call upload service
+	result, err := controller.uploadService.GetResumableUpload(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return the offset (never cached)
+	ctx.Header("Cache-Control", "no-store")
+	setResumableHeaders(ctx, result)
+	ctx.Header("Upload-Length", strconv.FormatInt(result.Length, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// PatchResumableUpload handles PATCH /api/v1/upload/resumable/:id
+// The body (application/offset+octet-stream) is appended at Upload-Offset.
+// Once complete, Upload-Attachment-Id is the attachment to send in SendMessageRequest.AttachmentIDs
+func (controller *uploadControllerImpl) PatchResumableUpload(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Chunks must be sent as application/offset+octet-stream
+	if ctx.ContentType() != "application/offset+octet-stream" {
+		ctx.JSON(http.StatusUnsupportedMediaType, web.ErrorResponse{
+			Success: false,
+			Message: "Content-Type must be application/offset+octet-stream",
+		})
+		return
+	}
+
+	// 3. Bind tus headers
+	var req web.PatchResumableUploadRequest
+	if err := ctx.ShouldBindHeader(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid upload headers",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 4. Call upload service
+	result, err := controller.uploadService.WriteResumableChunk(ctx.Request.Context(), userID, ctx.Param("id"), &req, ctx.Request.Body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 5. Return the new offset
+	setResumableHeaders(ctx, result)
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteResumableUpload handles DELETE /api/v1/upload/resumable/:id
+func (controller *uploadControllerImpl) DeleteResumableUpload(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call upload service
+	if err := controller.uploadService.DeleteResumableUpload(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetResumableUpload handles GET /api/v1/upload/resumable/:id
+// Returns the upload state as JSON, with the attachment once complete
+func (controller *uploadControllerImpl) GetResumableUpload(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call upload service
+	result, err := controller.uploadService.GetResumableUpload(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Upload retrieved successfully",
+		Data: result,
+	})
+}
+
+// setResumableHeaders sets the tus headers describing an upload's progress
+func setResumableHeaders(ctx *gin.Context, result *web.ResumableUploadResponse) {
+	ctx.Header("Upload-Offset", strconv.FormatInt(result.Offset, 10))
+	ctx.Header("Upload-Expires", result.ExpiresAt.UTC().Format(http.TimeFormat))
+	if result.Attachment != nil {
+		ctx.Header("Upload-Attachment-Id", result.Attachment.ID)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package user
+
+import "github.com/gin-gonic/gin"
+
+// UserController interface for user HTTP handlers
+type UserController interface {
+	// SearchUsers handles GET /users/search?q=
+	SearchUsers(ctx *gin.Context)
+
+	// GetUserByID handles GET /users/:id
+	GetUserByID(ctx *gin.Context)
+
+	// GetUserByUsername handles GET /users/by-username/:username
+	GetUserByUsername(ctx *gin.Context)
+
+	// BlockUser handles POST /users/:id/block
+	BlockUser(ctx *gin.Context)
+
+	// UnblockUser handles DELETE /users/:id/block
+	UnblockUser(ctx *gin.Context)
+
+	// GetBlockedUsers handles GET /users/blocked
+	GetBlockedUsers(ctx *gin.Context)
+
+	// UpdatePresence handles PUT /users/me/presence
+	UpdatePresence(ctx *gin.Context)
+}
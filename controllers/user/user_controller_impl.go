@@ -0,0 +1,202 @@
+package user
+
+import (
+	"chatapp-api/middleware"
+	"chatapp-api/models/web"
+	userService "chatapp-api/services/user"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userControllerImpl implements UserController interface
+type userControllerImpl struct {
+	userService userService.UserService
+}
+
+// NewUserController creates a new instance of UserController
+func NewUserController(userService userService.UserService) UserController {
+	return &userControllerImpl{userService: userService}
+}
+
+// SearchUsers handles GET /users/search?q=
+func (controller *userControllerImpl) SearchUsers(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Parse query and pagination (page & limit)
+	query := ctx.Query("q")
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	// 3. Call service to search users
+	result, pagination, err := controller.userService.SearchUsers(ctx.Request.Context(), userID, query, page, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.PaginatedResponse{
+		Success: true,
+		Message: "Users fetched successfully",
+		Data: result,
+		Pagination: *pagination,
+	})
+}
+
+// GetUserByID handles GET /users/:id
+func (controller *userControllerImpl) GetUserByID(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get public profile
+	result, err := controller.userService.GetPublicProfile(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User profile retrieved",
+		Data: result,
+	})
+}
+
+// GetUserByUsername handles GET /users/by-username/:username
+func (controller *userControllerImpl) GetUserByUsername(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get public profile
+	result, err := controller.userService.GetProfileByUsername(ctx.Request.Context(), userID, ctx.Param("username"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User profile retrieved",
+		Data: result,
+	})
+}
+
+// BlockUser handles POST /users/:id/block
+func (controller *userControllerImpl) BlockUser(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to block user
+	if err := controller.userService.BlockUser(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User blocked successfully",
+	})
+}
+
+// UnblockUser handles DELETE /users/:id/block
+func (controller *userControllerImpl) UnblockUser(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to unblock user
+	if err := controller.userService.UnblockUser(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "User unblocked successfully",
+	})
+}
+
+// GetBlockedUsers handles GET /users/blocked
+func (controller *userControllerImpl) GetBlockedUsers(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Call service to get blocked users
+	result, err := controller.userService.GetBlockedUsers(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 3. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Blocked users retrieved successfully",
+		Data: result,
+	})
+}
+
+// UpdatePresence handles PUT /users/me/presence
+func (controller *userControllerImpl) UpdatePresence(ctx *gin.Context) {
+	// 1. Get User ID from context (from JWT middleware)
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 2. Bind request body
+	var req web.UpdatePresenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, web.ErrorResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// 3. Call service to update presence
+	result, err := controller.userService.UpdatePresence(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// 4. Return response
+	ctx.JSON(http.StatusOK, web.ApiResponse{
+		Success: true,
+		Message: "Presence updated successfully",
+		Data: result,
+	})
+}
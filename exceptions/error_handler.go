@@ -44,6 +44,21 @@ func ErrorHandler() gin.HandlerFunc {
 					Success: false,
 					Message: e.Message,
 				})
+			case PayloadTooLargeError:
+				c.JSON(http.StatusRequestEntityTooLarge, web.ApiResponse{
+					Success: false,
+					Message: e.Message,
+				})
+			case TooManyRequestsError:
+				c.JSON(http.StatusTooManyRequests, web.ApiResponse{
+					Success: false,
+					Message: e.Message,
+				})
+			case MalwareDetectedError:
+				c.JSON(http.StatusUnprocessableEntity, web.ApiResponse{
+					Success: false,
+					Message: e.Message,
+				})
 			case InternalServerError:
 				c.JSON(http.StatusInternalServerError, web.ApiResponse{
 					Success: false,
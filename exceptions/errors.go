@@ -77,3 +77,42 @@ func NewInternalServerError(message string) InternalServerError {
 func (e InternalServerError) Error() string {
     return e.Message
 }
+
+// PayloadTooLargeError for Uploads Over the Size Limit
+type PayloadTooLargeError struct {
+	Message string
+}
+
+func NewPayloadTooLargeError(message string) PayloadTooLargeError {
+	return PayloadTooLargeError{Message: message}
+}
+
+func (e PayloadTooLargeError) Error() string {
+	return e.Message
+}
+
+// TooManyRequestsError for Rate Limited Requests
+type TooManyRequestsError struct {
+	Message string
+}
+
+func NewTooManyRequestsError(message string) TooManyRequestsError {
+	return TooManyRequestsError{Message: message}
+}
+
+func (e TooManyRequestsError) Error() string {
+	return e.Message
+}
+
+// MalwareDetectedError for Uploads Rejected by the Malware Scanner
+type MalwareDetectedError struct {
+	Message string
+}
+
+func NewMalwareDetectedError(message string) MalwareDetectedError {
+	return MalwareDetectedError{Message: message}
+}
+
+func (e MalwareDetectedError) Error() string {
+	return e.Message
+}
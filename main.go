@@ -3,23 +3,46 @@ package main
 import (
 	"chatapp-api/apps/database"
 	"chatapp-api/apps/redis"
+	"chatapp-api/apps/scanner"
+	"chatapp-api/apps/storage"
 	"chatapp-api/config"
 	"chatapp-api/routes"
 	"chatapp-api/websocket"
+	"context"
+	"flag"
 	"log"
+	"os"
 
 	authController "chatapp-api/controllers/auth"
+	contactController "chatapp-api/controllers/contact"
 	conversationController "chatapp-api/controllers/conversation"
+	inviteController "chatapp-api/controllers/invite"
 	messageController "chatapp-api/controllers/message"
 	uploadController "chatapp-api/controllers/upload"
+	userController "chatapp-api/controllers/user"
+	attachmentRepo "chatapp-api/repositories/attachment"
+	banRepo "chatapp-api/repositories/ban"
+	contactRepo "chatapp-api/repositories/contact"
 	conversationRepo "chatapp-api/repositories/conversation"
+	inviteRepo "chatapp-api/repositories/invite"
+	joinRequestRepo "chatapp-api/repositories/join_request"
 	messageRepo "chatapp-api/repositories/message"
+	mentionRepo "chatapp-api/repositories/message_mention"
 	receiptRepo "chatapp-api/repositories/message_receipt"
+	moderationLogRepo "chatapp-api/repositories/moderation_log"
+	presenceRepo "chatapp-api/repositories/presence"
+	resumableUploadRepo "chatapp-api/repositories/resumable_upload"
+	storedObjectRepo "chatapp-api/repositories/stored_object"
+	uploadReservationRepo "chatapp-api/repositories/upload_reservation"
 	userRepo "chatapp-api/repositories/user"
+	userBlockRepo "chatapp-api/repositories/user_block"
 	authService "chatapp-api/services/auth"
+	contactService "chatapp-api/services/contact"
 	conversationService "chatapp-api/services/conversation"
+	inviteService "chatapp-api/services/invite"
 	messageService "chatapp-api/services/message"
 	uploadService "chatapp-api/services/upload"
+	userService "chatapp-api/services/user"
 )
 
 func main() {
@@ -32,35 +55,74 @@ func main() {
 
 	// 3. Connect to Redis
 	redisClient := redis.ConnectRedis(config)
-	_ = redisClient
+
+	// 4. Initialize object storage (local disk, S3-compatible or Supabase)
+	objectStore := storage.NewObjectStore(config)
+
+	// Malware scanner checking uploads before they are stored (ClamAV or the EICAR test scanner)
+	fileScanner := scanner.NewScanner(config)
 
 	
-	// 4. Initialize repositories
+	// 5. Initialize repositories
 	userRepository := userRepo.NewUserRepository(db)
 	conversationRepository := conversationRepo.NewConversationRepository(db)
 	messageRepository := messageRepo.NewMessageRepository(db)
 	messageReceiptRepository := receiptRepo.NewMessageReceiptRepository(db)
+	messageMentionRepository := mentionRepo.NewMessageMentionRepository(db)
+	attachmentRepository := attachmentRepo.NewAttachmentRepository(db)
+	storedObjectRepository := storedObjectRepo.NewStoredObjectRepository(db)
+	resumableUploadRepository := resumableUploadRepo.NewResumableUploadRepository(db)
+	uploadReservationRepository := uploadReservationRepo.NewUploadReservationRepository(db)
+	moderationLogRepository := moderationLogRepo.NewModerationLogRepository(db)
+	inviteRepository := inviteRepo.NewInviteRepository(db)
+	joinRequestRepository := joinRequestRepo.NewJoinRequestRepository(db)
+	banRepository := banRepo.NewBanRepository(db)
+	userBlockRepository := userBlockRepo.NewUserBlockRepository(db)
+	contactRepository := contactRepo.NewContactRepository(db)
+	presenceRepository := presenceRepo.NewPresenceRepository(redisClient)
 	
-	// 5. Initialize WebSocket Hub
-	hub := websocket.NewHub(conversationRepository, userRepository, messageReceiptRepository)
+	// 6. Initialize WebSocket Hub
+	hub := websocket.NewHub(conversationRepository, userRepository, presenceRepository, messageReceiptRepository, userBlockRepository, contactRepository)
 	go hub.Run()
 
-	// 6. Initialize services
-	authService := authService.NewAuthService(userRepository, config)
-	conversationService := conversationService.NewConversationService(conversationRepository, userRepository)
-	messageService := messageService.NewMessageService(messageRepository, conversationRepository, messageReceiptRepository, hub)
-	uploadService := uploadService.NewUploadService(config)
+	// 7. Initialize services
+	authService := authService.NewAuthService(userRepository, presenceRepository, attachmentRepository, config)
+	conversationService := conversationService.NewConversationService(conversationRepository, userRepository, presenceRepository, messageRepository, moderationLogRepository, banRepository, userBlockRepository, contactRepository, attachmentRepository, hub)
+	messageService := messageService.NewMessageService(messageRepository, conversationRepository, messageReceiptRepository, messageMentionRepository, attachmentRepository, moderationLogRepository, presenceRepository, userBlockRepository, contactRepository, hub)
+	uploadService := uploadService.NewUploadService(objectStore, fileScanner, attachmentRepository, storedObjectRepository, resumableUploadRepository, uploadReservationRepository, messageRepository, conversationRepository, userRepository, config)
+	inviteService := inviteService.NewInviteService(inviteRepository, joinRequestRepository, conversationRepository, banRepository, presenceRepository, conversationService, hub)
+	userService := userService.NewUserService(userRepository, contactRepository, presenceRepository, userBlockRepository, hub)
+	contactService := contactService.NewContactService(contactRepository, userRepository, conversationRepository, presenceRepository, userBlockRepository, hub)
+
+	// Command "gc": remove orphaned uploads once and exit ("gc -dry-run" only logs what would be removed)
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		gcFlags := flag.NewFlagSet("gc", flag.ExitOnError)
+		dryRun := gcFlags.Bool("dry-run", config.Storage.GCDryRun, "only log what would be removed")
+		gcFlags.Parse(os.Args[2:])
+
+		if _, err := uploadService.CollectGarbage(context.Background(), *dryRun); err != nil {
+			log.Fatalf("Failed to collect orphaned uploads: %v", err)
+		}
+		return
+	}
+
+	// Remove abandoned resumable uploads and orphaned uploads in the background
+	go uploadService.RunResumableCleanup()
+	go uploadService.RunGarbageCollection()
 
-	// 7. Initialize controllers
+	// 8. Initialize controllers
 	authController := authController.NewAuthController(authService)
 	conversationController := conversationController.NewConversationController(conversationService)
 	messageController := messageController.NewMessageController(messageService)
 	uploadController := uploadController.NewUploadController(uploadService)
+	inviteController := inviteController.NewInviteController(inviteService)
+	userController := userController.NewUserController(userService)
+	contactController := contactController.NewContactController(contactService)
 
-	// 8. Setup router
-	router := routes.SetupRouter(config, authController, conversationController, messageController, uploadController, hub)
+	// 9. Setup router
+	router := routes.SetupRouter(config, authController, conversationController, messageController, uploadController, inviteController, userController, contactController, hub)
 
-	// 9. Start server
+	// 10. Start server
 	log.Printf("⏳ Attempting to start server on port %s...", config.App.Port)
 	if err := router.Run(":" + config.App.Port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
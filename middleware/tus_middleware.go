@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"chatapp-api/config"
+	"chatapp-api/models/web"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TusVersion is the only version of the tus resumable upload protocol supported
+const TusVersion = "1.0.0"
+
+// TusMiddleware adds the tus protocol headers to resumable upload responses and checks the client's protocol version
+func TusMiddleware(config *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. Every response tells the protocol version
+		c.Header("Tus-Resumable", TusVersion)
+
+		// 2. Discovery (OPTIONS): supported versions, extensions and maximum size
+		if c.Request.Method == http.MethodOptions {
+			c.Header("Tus-Version", TusVersion)
+			c.Header("Tus-Extension", "creation,expiration,termination")
+			c.Header("Tus-Max-Size", strconv.FormatInt(int64(config.Storage.ResumableMaxSizeMB)*1024*1024, 10))
+			c.Next()
+			return
+		}
+
+		// 3. Other tus requests must use a supported version (GET isn't part of tus, it returns JSON)
+		if c.Request.Method != http.MethodGet && c.GetHeader("Tus-Resumable") != TusVersion {
+			c.Header("Tus-Version", TusVersion)
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed,
+			web.ApiResponse{
+				Success: false,
+				Message: "Unsupported Tus-Resumable version, use " + TusVersion,
+			})
+			return
+		}
+
+		// 4. Continue to next handler
+		c.Next()
+	}
+}
\ No newline at end of file
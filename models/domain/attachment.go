@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentURLPrefix is the path of the media route, an attachment's URL is AttachmentURLPrefix + its ID
+const AttachmentURLPrefix = "/api/v1/media/"
+
+// Malware scan statuses of an attachment (infected files are rejected, never stored)
+const (
+	ScanStatusClean     = "clean"     // The scanner found nothing
+	ScanStatusFailed    = "failed"    // The scanner was unavailable and the file was accepted anyway (SCANNER_FAIL_OPEN)
+	ScanStatusUnscanned = "unscanned" // Uploaded before malware scanning, or only checked by the test scanner
+)
+
+// Attachment is an uploaded file, linked to the message it was sent with
+type Attachment struct {
+	// Unique ID for this attachment (att_xxx), also the media ID of GET /media/:id
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Who uploaded the file (FK to users)
+	UploaderID string `gorm:"type:varchar(32);not null" json:"uploader_id"`
+
+	// Message the file was sent with (null until sent)
+	MessageID *string `gorm:"type:varchar(32)" json:"message_id,omitempty"`
+
+	// Order of the attachment in its message
+	Position int `gorm:"not null;default:0" json:"-"`
+
+	// Key of the file in the object store (private, downloads go through signed URLs), shared by
+	// attachments with the same content (see StoredObject)
+	ObjectKey string `gorm:"type:varchar(255);not null" json:"-"`
+
+	Filename string `gorm:"type:varchar(255);not null" json:"filename"`
+	MimeType string `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size     int64  `gorm:"not null" json:"size"`
+
+	// Pixel dimensions and duration in milliseconds, when known
+	Width      *int `json:"width,omitempty"`
+	Height     *int `json:"height,omitempty"`
+	DurationMs *int `json:"duration_ms,omitempty"`
+
+	// Resized copies and BlurHash placeholder (images only)
+	Thumbnails AttachmentThumbnails `gorm:"type:jsonb" json:"thumbnails,omitempty"`
+	Blurhash   *string              `gorm:"type:varchar(64)" json:"blurhash,omitempty"`
+
+	// Hex SHA-256 of the file content
+	SHA256 string `gorm:"column:sha256;type:char(64);not null" json:"sha256"`
+
+	// Malware scan result: status, scanner that checked the file and when
+	ScanStatus string     `gorm:"type:varchar(20);not null;default:unscanned" json:"scan_status"`
+	ScannedBy  *string    `gorm:"type:varchar(50)" json:"scanned_by,omitempty"`
+	ScannedAt  *time.Time `json:"scanned_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Media URL (GET /media/:id), not stored
+	URL string `gorm:"-" json:"url"`
+
+	// Relations
+	Uploader User `gorm:"foreignKey:UploaderID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (attachment *Attachment) TableName() string {
+	return "attachments"
+}
+
+// BeforeCreate hook to auto-generate ID with "att_" prefix
+func (attachment *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if attachment.ID == "" {
+		attachment.ID = utils.GenerateID("att")
+	}
+	attachment.fillURLs()
+	return nil
+}
+
+// AfterFind hook to fill the media URLs
+func (attachment *Attachment) AfterFind(tx *gorm.DB) error {
+	attachment.fillURLs()
+	return nil
+}
+
+// fillURLs sets the media URLs of the attachment and its thumbnails
+func (attachment *Attachment) fillURLs() {
+	attachment.URL = AttachmentURLPrefix + attachment.ID
+	for idx := range attachment.Thumbnails {
+		attachment.Thumbnails[idx].URL = attachment.URL + "?thumbnail=" + attachment.Thumbnails[idx].Name
+	}
+}
+
+// CanBeAvatarOf checks whether the user can set the attachment as a profile or group picture:
+// only an image they uploaded themself
+func (attachment *Attachment) CanBeAvatarOf(userID string) bool {
+	return attachment.UploaderID == userID && strings.HasPrefix(attachment.MimeType, "image/")
+}
+
+// IsAttachmentURL checks whether a URL points to the media route, uploaded pictures must be set by attachment ID
+// (an avatar URL alone never grants access to a file)
+func IsAttachmentURL(url string) bool {
+	return strings.Contains(url, AttachmentURLPrefix)
+}
+
+// FindThumbnail returns the thumbnail with the given name, nil if the attachment has none
+func (attachment *Attachment) FindThumbnail(name string) *AttachmentThumbnail {
+	for idx := range attachment.Thumbnails {
+		if attachment.Thumbnails[idx].Name == name {
+			return &attachment.Thumbnails[idx]
+		}
+	}
+	return nil
+}
+
+// AttachmentThumbnail is a resized JPEG copy of an image attachment
+type AttachmentThumbnail struct {
+	Name   string `json:"name"` // e.g. "small", "medium"
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"` // GET /media/:id?thumbnail=<name>
+}
+
+// ThumbnailObjectKey returns the storage key of a thumbnail of a stored file, next to which it is shared
+// (e.g. "images/<sha256>.jpg" gives "thumbnails/<sha256>_small.jpg")
+func ThumbnailObjectKey(objectKey, name string) string {
+	base := path.Base(objectKey)
+	return "thumbnails/" + strings.TrimSuffix(base, path.Ext(base)) + "_" + name + ".jpg"
+}
+
+// AttachmentThumbnails is the list of thumbnails of an attachment, stored as JSON
+type AttachmentThumbnails []AttachmentThumbnail
+
+// Value stores the thumbnails as JSON in database (NULL when there are none)
+func (thumbnails AttachmentThumbnails) Value() (driver.Value, error) {
+	if len(thumbnails) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(thumbnails)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the JSON thumbnails from database
+func (thumbnails *AttachmentThumbnails) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*thumbnails = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, thumbnails)
+	case string:
+		return json.Unmarshal([]byte(data), thumbnails)
+	default:
+		return errors.New("unsupported type for AttachmentThumbnails")
+	}
+}
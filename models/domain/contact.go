@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Contact statuses
+const (
+	ContactStatusPending  = "pending"
+	ContactStatusAccepted = "accepted"
+)
+
+// Contact is a contact request between two users, and once accepted a contact of both
+type Contact struct {
+	// Unique ID for this contact (cnt_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Who sent the request (FK to users)
+	RequesterID string `gorm:"type:varchar(32);not null" json:"requester_id"`
+
+	// Who received the request (FK to users)
+	AddresseeID string `gorm:"type:varchar(32);not null" json:"addressee_id"`
+
+	// Current status: "pending" or "accepted"
+	Status string `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// When the request was accepted (null = still pending)
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+
+	// Relations
+	Requester User `gorm:"foreignKey:RequesterID" json:"-"`
+	Addressee User `gorm:"foreignKey:AddresseeID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (contact *Contact) TableName() string {
+	return "contacts"
+}
+
+// BeforeCreate hook to auto-generate ID with "cnt_" prefix
+func (contact *Contact) BeforeCreate(tx *gorm.DB) error {
+	if contact.ID == "" {
+		contact.ID = utils.GenerateID("cnt")
+	}
+	return nil
+}
+
+// OtherUser returns the user on the other side of the contact
+func (contact *Contact) OtherUser(userID string) *User {
+	if contact.RequesterID == userID {
+		return &contact.Addressee
+	}
+	return &contact.Requester
+}
@@ -9,14 +9,15 @@ import (
 
 // Conversation model
 type Conversation struct {
-	ID        string         `gorm:"type:varchar(32);primaryKey" json:"id"`
-	Name      *string        `gorm:"type:varchar(100)" json:"name,omitempty"`
-	AvatarURL *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
-	Type      string         `gorm:"type:varchar(20);not null;default:'direct'" json:"type"`
-	CreatedBy string         `gorm:"type:varchar(32);not null" json:"created_by"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 string         `gorm:"type:varchar(32);primaryKey" json:"id"`
+	Name               *string        `gorm:"type:varchar(100)" json:"name,omitempty"`
+	AvatarURL          *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
+	AvatarAttachmentID *string        `gorm:"type:varchar(32)" json:"-"` // Uploaded picture shown by AvatarURL, if any
+	Type               string         `gorm:"type:varchar(20);not null;default:'direct'" json:"type"`
+	CreatedBy          string         `gorm:"type:varchar(32);not null" json:"created_by"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 	
 	// Relations
 	Participants []Participant `gorm:"foreignKey:ConversationID" json:"participants,omitempty"`
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConversationBan prevents a user from being added to or joining a group
+type ConversationBan struct {
+	// Unique ID for this ban (ban_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Which group the user is banned from (FK to conversations)
+	ConversationID string `gorm:"type:varchar(32);not null" json:"conversation_id"`
+
+	// Who is banned (FK to users)
+	UserID string `gorm:"type:varchar(32);not null" json:"user_id"`
+
+	// Who issued the ban (FK to users)
+	BannedBy string `gorm:"type:varchar(32);not null" json:"banned_by"`
+
+	// Optional reason given by the moderator
+	Reason *string `gorm:"type:text" json:"reason,omitempty"`
+
+	// When the ban ends (null = permanent)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (ban *ConversationBan) TableName() string {
+	return "conversation_bans"
+}
+
+// BeforeCreate hook to auto-generate ID with "ban_" prefix
+func (ban *ConversationBan) BeforeCreate(tx *gorm.DB) error {
+	if ban.ID == "" {
+		ban.ID = utils.GenerateID("ban")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConversationInvite is a shareable invite link for a group
+type ConversationInvite struct {
+	// Unique ID for this invite (inv_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Which group the invite is for (FK to conversations)
+	ConversationID string `gorm:"type:varchar(32);not null" json:"conversation_id"`
+
+	// Shareable code used to join
+	Code string `gorm:"type:varchar(32);uniqueIndex;not null" json:"code"`
+
+	// Who created the invite (FK to users)
+	CreatedBy string `gorm:"type:varchar(32);not null" json:"created_by"`
+
+	// When the invite stops working (null = never expires)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Maximum number of uses (null = unlimited)
+	MaxUses *int `json:"max_uses,omitempty"`
+
+	// How many times the invite has been used
+	UseCount int `gorm:"not null;default:0" json:"use_count"`
+
+	// If true, joining creates a join request that an admin must approve
+	RequireApproval bool `gorm:"not null;default:false" json:"require_approval"`
+
+	// When the invite was revoked (null = still active)
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Conversation Conversation `gorm:"foreignKey:ConversationID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (invite *ConversationInvite) TableName() string {
+	return "conversation_invites"
+}
+
+// BeforeCreate hook to auto-generate ID with "inv_" prefix
+func (invite *ConversationInvite) BeforeCreate(tx *gorm.DB) error {
+	if invite.ID == "" {
+		invite.ID = utils.GenerateID("inv")
+	}
+	return nil
+}
+
+// IsUsable checks whether the invite can still be used to join
+func (invite *ConversationInvite) IsUsable(now time.Time) bool {
+	if invite.RevokedAt != nil {
+		return false
+	}
+	if invite.ExpiresAt != nil && now.After(*invite.ExpiresAt) {
+		return false
+	}
+	if invite.MaxUses != nil && invite.UseCount >= *invite.MaxUses {
+		return false
+	}
+	return true
+}
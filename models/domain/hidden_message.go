@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HiddenMessage marks a message as hidden for a single user ("delete for me")
+// Other participants still see the message as usual
+type HiddenMessage struct {
+	// Unique ID for this entry (hid_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Which message is hidden (FK to messages)
+	MessageID string `gorm:"type:varchar(32);not null" json:"message_id"`
+
+	// Which user hid the message (FK to users)
+	UserID string `gorm:"type:varchar(32);not null" json:"user_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName defines the table name in database
+func (hidden *HiddenMessage) TableName() string {
+	return "hidden_messages"
+}
+
+// BeforeCreate hook to auto-generate ID with "hid_" prefix
+func (hidden *HiddenMessage) BeforeCreate(tx *gorm.DB) error {
+	if hidden.ID == "" {
+		hidden.ID = utils.GenerateID("hid")
+	}
+	return nil
+}
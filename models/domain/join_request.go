@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Join request statuses
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinRequest is created when a user joins through an invite that requires approval
+type JoinRequest struct {
+	// Unique ID for this request (jreq_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Which group the user wants to join (FK to conversations)
+	ConversationID string `gorm:"type:varchar(32);not null" json:"conversation_id"`
+
+	// Which invite was used (FK to conversation_invites)
+	InviteID string `gorm:"type:varchar(32);not null" json:"invite_id"`
+
+	// Who wants to join (FK to users)
+	UserID string `gorm:"type:varchar(32);not null" json:"user_id"`
+
+	// Current status: "pending", "approved", or "rejected"
+	Status string `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+
+	// Which admin reviewed the request (null = not reviewed yet)
+	ReviewedBy *string `gorm:"type:varchar(32)" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName defines the table name in database
+func (request *JoinRequest) TableName() string {
+	return "join_requests"
+}
+
+// BeforeCreate hook to auto-generate ID with "jreq_" prefix
+func (request *JoinRequest) BeforeCreate(tx *gorm.DB) error {
+	if request.ID == "" {
+		request.ID = utils.GenerateID("jreq")
+	}
+	return nil
+}
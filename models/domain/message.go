@@ -2,27 +2,79 @@ package domain
 
 import (
 	"chatapp-api/utils"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// MessageTypeSystem is the type of messages generated by the server (membership and settings changes)
+const MessageTypeSystem = "system"
+
+// System message actions
+const (
+	SystemActionMemberAdded         = "member_added"
+	SystemActionMemberRemoved       = "member_removed"
+	SystemActionMemberLeft          = "member_left"
+	SystemActionMemberJoined        = "member_joined"
+	SystemActionConversationRenamed = "conversation_renamed"
+	SystemActionAvatarChanged       = "avatar_changed"
+)
+
+// SystemPayload is the structured payload of a system message
+// e.g. {"action":"member_added","actor":"user_xxx","targets":["user_yyy"]}
+type SystemPayload struct {
+	Action    string   `json:"action"`
+	Actor     string   `json:"actor"`
+	Targets   []string `json:"targets,omitempty"`
+	Name      *string  `json:"name,omitempty"`       // New name for "conversation_renamed"
+	AvatarURL *string  `json:"avatar_url,omitempty"` // New avatar for "avatar_changed"
+}
+
+// Value stores the payload as JSON in database
+func (payload SystemPayload) Value() (driver.Value, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the JSON payload from database
+func (payload *SystemPayload) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, payload)
+	case string:
+		return json.Unmarshal([]byte(data), payload)
+	default:
+		return errors.New("unsupported type for SystemPayload")
+	}
+}
+
 // Message model
 type Message struct {
-	ID             string         `gorm:"type:varchar(32);primaryKey" json:"id"`
-	ConversationID string         `gorm:"type:varchar(32);not null" json:"conversation_id"`
-	SenderID       string         `gorm:"type:varchar(32);not null" json:"sender_id"`
-	Content        string         `gorm:"type:text;not null" json:"content"`
-	Caption        *string        `gorm:"type:text" json:"caption,omitempty"`
-	Type           string         `gorm:"type:varchar(20);default:'text'" json:"type"`
-	IsEdited       bool           `gorm:"type:boolean;default:false" json:"is_edited"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             string          `gorm:"type:varchar(32);primaryKey" json:"id"`
+	ConversationID string          `gorm:"type:varchar(32);not null" json:"conversation_id"`
+	SenderID       string          `gorm:"type:varchar(32);not null" json:"sender_id"`
+	Content        string          `gorm:"type:text;not null" json:"content"`
+	Caption        *string         `gorm:"type:text" json:"caption,omitempty"`
+	Type           string          `gorm:"type:varchar(20);default:'text'" json:"type"`
+	IsEdited       bool            `gorm:"type:boolean;default:false" json:"is_edited"`
+	IsDeleted      bool            `gorm:"type:boolean;default:false" json:"is_deleted"`
+	DeletedBy      *string         `gorm:"type:varchar(32)" json:"deleted_by,omitempty"`
+	Payload        *SystemPayload  `gorm:"type:jsonb" json:"payload,omitempty"` // Only for "system" messages
+	Mentions       MentionEntities `gorm:"type:jsonb" json:"mentions,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `gorm:"index" json:"-"`
 	
 	// Relations
 	Sender       User         `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
 	Conversation Conversation `gorm:"foreignKey:ConversationID" json:"-"`
+	Attachments  []Attachment `gorm:"foreignKey:MessageID" json:"attachments,omitempty"`
 }
 
 // TableName to define table name
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Mention types
+const (
+	MentionTypeUser = "user" // @username or @user_<id>
+	MentionTypeAll  = "all"  // @all: every participant
+	MentionTypeHere = "here" // @here: participants online when the message is sent
+)
+
+// mentionPattern matches "@handle" at the start of the text or after a character that can't be part of a handle
+// (so "bob@example.com" isn't a mention)
+var mentionPattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_@])(@[a-zA-Z][a-zA-Z0-9_]*)`)
+
+// MentionToken is an "@handle" found in a text, before it is resolved to users
+type MentionToken struct {
+	Handle string // Without the "@"
+	Offset int    // In characters (Unicode code points), "@" included
+	Length int    // In characters, "@" included
+}
+
+// ParseMentionTokens finds all "@handle" tokens in a text
+func ParseMentionTokens(text string) []MentionToken {
+	var tokens []MentionToken
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		tokens = append(tokens, MentionToken{
+			Handle: text[start+1 : end],
+			Offset: utf8.RuneCountInString(text[:start]),
+			Length: end - start, // Handles are ASCII only
+		})
+	}
+	return tokens
+}
+
+// MentionKind returns the mention type of a handle ("all", "here" or "user")
+func (token MentionToken) MentionKind() string {
+	switch strings.ToLower(token.Handle) {
+	case MentionTypeAll:
+		return MentionTypeAll
+	case MentionTypeHere:
+		return MentionTypeHere
+	default:
+		return MentionTypeUser
+	}
+}
+
+// MentionEntity is a resolved mention in a message's text (content for text messages, caption otherwise)
+type MentionEntity struct {
+	Type   string  `json:"type"`
+	UserID *string `json:"user_id,omitempty"` // Only for "user" mentions
+	Offset int     `json:"offset"`            // In characters (Unicode code points)
+	Length int     `json:"length"`
+}
+
+// MentionEntities is the list of mentions of a message, stored as JSON
+type MentionEntities []MentionEntity
+
+// Value stores the mentions as JSON in database (NULL when there are none)
+func (entities MentionEntities) Value() (driver.Value, error) {
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(entities)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the JSON mentions from database
+func (entities *MentionEntities) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*entities = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, entities)
+	case string:
+		return json.Unmarshal([]byte(data), entities)
+	default:
+		return errors.New("unsupported type for MentionEntities")
+	}
+}
+
+// MessageMention is a user mentioned in a message (@all and @here give one per mentioned participant)
+type MessageMention struct {
+	ID             string    `gorm:"type:varchar(32);primaryKey" json:"id"`
+	MessageID      string    `gorm:"type:varchar(32);not null" json:"message_id"`
+	ConversationID string    `gorm:"type:varchar(32);not null" json:"conversation_id"`
+	UserID         string    `gorm:"type:varchar(32);not null" json:"user_id"`
+	Type           string    `gorm:"type:varchar(10);not null;default:'user'" json:"type"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relations
+	Message Message `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+	User    User    `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (mention *MessageMention) TableName() string {
+	return "message_mentions"
+}
+
+// BeforeCreate hook to auto-generate ID with "mnt_" prefix
+func (mention *MessageMention) BeforeCreate(tx *gorm.DB) error {
+	if mention.ID == "" {
+		mention.ID = utils.GenerateID("mnt")
+	}
+	return nil
+}
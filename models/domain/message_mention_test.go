@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMentionTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []MentionToken
+	}{
+		{name: "no mention", text: "hello world", want: nil},
+		{name: "start of text", text: "@alice hi", want: []MentionToken{{Handle: "alice", Offset: 0, Length: 6}}},
+		{name: "after a space", text: "hi @bob_2", want: []MentionToken{{Handle: "bob_2", Offset: 3, Length: 6}}},
+		{
+			name: "several, punctuation ends a handle",
+			text: "@alice, @bob. (@carol)",
+			want: []MentionToken{
+				{Handle: "alice", Offset: 0, Length: 6},
+				{Handle: "bob", Offset: 8, Length: 4},
+				{Handle: "carol", Offset: 15, Length: 6},
+			},
+		},
+		{name: "email address", text: "mail bob@example.com", want: nil},
+		{name: "glued to another mention", text: "@alice@bob", want: []MentionToken{{Handle: "alice", Offset: 0, Length: 6}}},
+		{name: "handle must start with a letter", text: "@1alice @_bob", want: nil},
+		{name: "lone at sign", text: "meet @ noon", want: nil},
+		{name: "keyword", text: "@all look", want: []MentionToken{{Handle: "all", Offset: 0, Length: 4}}},
+		{
+			name: "offsets count characters, not bytes",
+			text: "héllo 👋 @zoé",
+			want: []MentionToken{{Handle: "zo", Offset: 8, Length: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseMentionTokens(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMentionTokens(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentionKind(t *testing.T) {
+	tests := []struct {
+		handle string
+		want   string
+	}{
+		{handle: "all", want: MentionTypeAll},
+		{handle: "ALL", want: MentionTypeAll},
+		{handle: "here", want: MentionTypeHere},
+		{handle: "alice", want: MentionTypeUser},
+		{handle: "allison", want: MentionTypeUser},
+	}
+
+	for _, tt := range tests {
+		if got := (MentionToken{Handle: tt.handle}).MentionKind(); got != tt.want {
+			t.Errorf("MentionKind(%q) = %q, want %q", tt.handle, got, tt.want)
+		}
+	}
+}
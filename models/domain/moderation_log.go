@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Moderation actions
+const (
+	ModerationActionDeleteMessage = "delete_message"
+	ModerationActionBanUser       = "ban_user"
+	ModerationActionUnbanUser     = "unban_user"
+)
+
+// ModerationLog records an action taken by a group admin against another member
+type ModerationLog struct {
+	// Unique ID for this entry (mod_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// In which conversation the action happened (FK to conversations)
+	ConversationID string `gorm:"type:varchar(32);not null" json:"conversation_id"`
+
+	// Who took the action (FK to users)
+	ActorID string `gorm:"type:varchar(32);not null" json:"actor_id"`
+
+	// Whose content was moderated (FK to users)
+	TargetUserID string `gorm:"type:varchar(32);not null" json:"target_user_id"`
+
+	// Which message was moderated (null = not tied to a message)
+	MessageID *string `gorm:"type:varchar(32)" json:"message_id,omitempty"`
+
+	// What was done, e.g. "delete_message"
+	Action string `gorm:"type:varchar(30);not null" json:"action"`
+
+	// Optional reason given by the admin
+	Reason *string `gorm:"type:text" json:"reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Actor      User `gorm:"foreignKey:ActorID" json:"-"`
+	TargetUser User `gorm:"foreignKey:TargetUserID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (moderationLog *ModerationLog) TableName() string {
+	return "moderation_logs"
+}
+
+// BeforeCreate hook to auto-generate ID with "mod_" prefix
+func (moderationLog *ModerationLog) BeforeCreate(tx *gorm.DB) error {
+	if moderationLog.ID == "" {
+		moderationLog.ID = utils.GenerateID("mod")
+	}
+	return nil
+}
@@ -9,11 +9,12 @@ import (
 
 // Participant model
 type Participant struct {
-	ID             string    `gorm:"type:varchar(32);primaryKey" json:"id"`
-	UserID         string    `gorm:"type:varchar(32);not null" json:"user_id"`
-	ConversationID string    `gorm:"type:varchar(32);not null" json:"conversation_id"`
-	Role           string    `gorm:"type:varchar(20);default:'member'" json:"role"`
-	JoinedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
+	ID               string    `gorm:"type:varchar(32);primaryKey" json:"id"`
+	UserID           string    `gorm:"type:varchar(32);not null" json:"user_id"`
+	ConversationID   string    `gorm:"type:varchar(32);not null" json:"conversation_id"`
+	Role             string    `gorm:"type:varchar(20);default:'member'" json:"role"`
+	JoinedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
+	IsMessageRequest bool      `gorm:"not null;default:false" json:"-"` // DM from a non-contact, waiting in the message request inbox
 	
 	// Relations
 	User         User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// Presence states
+const (
+	PresenceOnline  = "online"
+	PresenceIdle    = "idle" // Connected but inactive for a while
+	PresenceDND     = "dnd"  // Do not disturb (set by the user)
+	PresenceOffline = "offline"
+)
+
+// Custom status limits
+const (
+	MaxStatusTextLength  = 140
+	MaxStatusEmojiLength = 32
+)
+
+// ResolvePresence works out the presence state of a user
+// Offline when not connected, DND overrides idle, idle after inactivity, otherwise online
+func ResolvePresence(connected, doNotDisturb, idle bool) string {
+	switch {
+	case !connected:
+		return PresenceOffline
+	case doNotDisturb:
+		return PresenceDND
+	case idle:
+		return PresenceIdle
+	default:
+		return PresenceOnline
+	}
+}
+
+// PresenceUpdate is a change of presence settings requested by the user (REST or websocket)
+type PresenceUpdate struct {
+	// "online" turns DND off, "dnd" turns it on (idle is automatic)
+	Status *string
+
+	// Custom status, replaces the current one when text or emoji is given
+	StatusText  *string
+	StatusEmoji *string
+
+	// Custom status expires after this many minutes (nil = never)
+	ExpiresInMinutes *int
+
+	// ClearStatus removes the custom status
+	ClearStatus bool
+}
+
+// Validate checks the presence update
+func (update *PresenceUpdate) Validate() error {
+	if update.Status != nil && *update.Status != PresenceOnline && *update.Status != PresenceDND {
+		return errors.New("status must be online or dnd")
+	}
+	if update.StatusText != nil && utf8.RuneCountInString(*update.StatusText) > MaxStatusTextLength {
+		return errors.New("status text is too long")
+	}
+	if update.StatusEmoji != nil && utf8.RuneCountInString(*update.StatusEmoji) > MaxStatusEmojiLength {
+		return errors.New("status emoji is too long")
+	}
+	if update.ExpiresInMinutes != nil && *update.ExpiresInMinutes <= 0 {
+		return errors.New("expires_in_minutes must be positive")
+	}
+	return nil
+}
+
+// ApplyPresenceUpdate applies a presence update to the user's settings
+func (user *User) ApplyPresenceUpdate(update *PresenceUpdate, now time.Time) {
+	if update.Status != nil {
+		user.DoNotDisturb = *update.Status == PresenceDND
+	}
+
+	if update.ClearStatus {
+		user.StatusText = nil
+		user.StatusEmoji = nil
+		user.StatusExpiresAt = nil
+		return
+	}
+
+	if update.StatusText != nil || update.StatusEmoji != nil {
+		user.StatusText = nonEmpty(update.StatusText)
+		user.StatusEmoji = nonEmpty(update.StatusEmoji)
+		user.StatusExpiresAt = nil
+
+		if update.ExpiresInMinutes != nil {
+			expiresAt := now.Add(time.Duration(*update.ExpiresInMinutes) * time.Minute)
+			user.StatusExpiresAt = &expiresAt
+		}
+	}
+}
+
+// ActiveCustomStatus returns the custom status text and emoji, or nil once expired
+func (user *User) ActiveCustomStatus(now time.Time) (*string, *string) {
+	if user.StatusExpiresAt != nil && !now.Before(*user.StatusExpiresAt) {
+		return nil, nil
+	}
+	return user.StatusText, user.StatusEmoji
+}
+
+// ClearExpiredStatus removes the custom status once it has expired
+func (user *User) ClearExpiredStatus(now time.Time) {
+	if user.StatusExpiresAt != nil && !now.Before(*user.StatusExpiresAt) {
+		user.StatusText = nil
+		user.StatusEmoji = nil
+		user.StatusExpiresAt = nil
+	}
+}
+
+// nonEmpty returns nil for a nil or empty string
+func nonEmpty(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
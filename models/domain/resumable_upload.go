@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResumableUpload is a tus upload in progress, its bytes are kept on disk until Offset reaches Length
+type ResumableUpload struct {
+	// Unique ID for this upload (rup_xxx), part of its tus URL
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Who is uploading (FK to users)
+	UploaderID string `gorm:"type:varchar(32);not null" json:"uploader_id"`
+
+	// Original file name and total size in bytes (Upload-Length)
+	Filename string `gorm:"type:varchar(255);not null" json:"filename"`
+	Length   int64  `gorm:"not null" json:"length"`
+
+	// Number of bytes received so far (Upload-Offset)
+	Offset int64 `gorm:"column:upload_offset;not null;default:0" json:"offset"`
+
+	// Options of POST /upload sent in Upload-Metadata
+	DurationMs   *int `json:"duration_ms,omitempty"`
+	KeepMetadata bool `gorm:"not null;default:false" json:"keep_metadata"`
+
+	// Attachment created once the upload is complete (null until then)
+	AttachmentID *string `gorm:"type:varchar(32)" json:"attachment_id,omitempty"`
+
+	// When the upload is abandoned (pushed back by every chunk)
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+
+	// Lease of the request writing the upload (one at a time), free once LockedUntil has passed
+	LockToken   *string    `gorm:"type:varchar(32)" json:"-"`
+	LockedUntil *time.Time `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName defines the table name in database
+func (upload *ResumableUpload) TableName() string {
+	return "resumable_uploads"
+}
+
+// BeforeCreate hook to auto-generate ID with "rup_" prefix
+func (upload *ResumableUpload) BeforeCreate(tx *gorm.DB) error {
+	if upload.ID == "" {
+		upload.ID = utils.GenerateID("rup")
+	}
+	return nil
+}
+
+// IsComplete checks whether all bytes were received and the attachment created
+func (upload *ResumableUpload) IsComplete() bool {
+	return upload.AttachmentID != nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+// Participant roles in a conversation, from highest to lowest
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+)
+
+// Permissions that can be granted to a role
+const (
+	PermissionUpdateConversation = "update_conversation"
+	PermissionAddParticipants    = "add_participants"
+	PermissionKickParticipants   = "kick_participants"
+	PermissionDeleteMessages     = "delete_messages"
+	PermissionViewModerationLog  = "view_moderation_log"
+	PermissionManageRoles        = "manage_roles"
+	PermissionTransferOwnership  = "transfer_ownership"
+	PermissionManageInvites      = "manage_invites"
+	PermissionBanParticipants    = "ban_participants"
+)
+
+// roleRanks defines the hierarchy: a participant can only act on roles ranked below their own
+var roleRanks = map[string]int{
+	RoleOwner:     4,
+	RoleAdmin:     3,
+	RoleModerator: 2,
+	RoleMember:    1,
+}
+
+// rolePermissions is the permission matrix for group conversations
+var rolePermissions = map[string]map[string]bool{
+	RoleOwner: {
+		PermissionUpdateConversation: true,
+		PermissionAddParticipants:    true,
+		PermissionKickParticipants:   true,
+		PermissionDeleteMessages:     true,
+		PermissionViewModerationLog:  true,
+		PermissionManageRoles:        true,
+		PermissionTransferOwnership:  true,
+		PermissionManageInvites:      true,
+		PermissionBanParticipants:    true,
+	},
+	RoleAdmin: {
+		PermissionUpdateConversation: true,
+		PermissionAddParticipants:    true,
+		PermissionKickParticipants:   true,
+		PermissionDeleteMessages:     true,
+		PermissionViewModerationLog:  true,
+		PermissionManageRoles:        true,
+		PermissionManageInvites:      true,
+		PermissionBanParticipants:    true,
+	},
+	RoleModerator: {
+		PermissionKickParticipants:  true,
+		PermissionDeleteMessages:    true,
+		PermissionViewModerationLog: true,
+		PermissionBanParticipants:   true,
+	},
+	RoleMember: {},
+}
+
+// HasPermission checks whether a role is granted a permission
+func HasPermission(role, permission string) bool {
+	return rolePermissions[role][permission]
+}
+
+// RoleRank returns the rank of a role (0 for unknown roles)
+func RoleRank(role string) int {
+	return roleRanks[role]
+}
+
+// IsValidRole checks whether a role exists
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
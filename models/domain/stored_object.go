@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// StoredObject is a file in the object store. Its key is derived from its content, so attachments
+// with the same content share it; RefCount tracks how many do
+type StoredObject struct {
+	// Key of the file in the object store (e.g. "images/<sha256>.jpg")
+	ObjectKey string `gorm:"type:varchar(255);primaryKey" json:"object_key"`
+
+	// Hex SHA-256 of the file content
+	SHA256 string `gorm:"column:sha256;type:char(64);not null" json:"sha256"`
+
+	MimeType string `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size     int64  `gorm:"not null" json:"size"`
+
+	// Number of attachments using the object (0 = its deletion from the object store failed, to retry)
+	RefCount int `gorm:"not null;default:0" json:"ref_count"`
+
+	// Whether the file was written to the object store (its first upload may still be storing it, or have failed),
+	// and the thumbnails stored with it
+	Ready      bool                 `gorm:"not null;default:false" json:"ready"`
+	Thumbnails AttachmentThumbnails `gorm:"type:jsonb" json:"thumbnails,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName defines the table name in database
+func (object *StoredObject) TableName() string {
+	return "stored_objects"
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadReservation is an upload counted towards the user's storage quota and hourly upload limit
+// until its attachment is recorded
+type UploadReservation struct {
+	// Unique ID for this reservation (urs_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Who is uploading (FK to users)
+	UserID string `gorm:"type:varchar(32);not null" json:"user_id"`
+
+	// Size in bytes of the file being uploaded
+	Size int64 `gorm:"not null" json:"size"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName defines the table name in database
+func (reservation *UploadReservation) TableName() string {
+	return "upload_reservations"
+}
+
+// BeforeCreate hook to auto-generate ID with "urs_" prefix
+func (reservation *UploadReservation) BeforeCreate(tx *gorm.DB) error {
+	if reservation.ID == "" {
+		reservation.ID = utils.GenerateID("urs")
+	}
+	return nil
+}
@@ -7,18 +7,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// Presence visibility settings (who can see is_online and last_seen)
+const (
+	PresenceVisibilityEveryone = "everyone"
+	PresenceVisibilityContacts = "contacts"
+	PresenceVisibilityNobody   = "nobody"
+)
+
 // User model
 type User struct {
-	ID        string         `gorm:"type:varchar(32);primaryKey" json:"id"`
-	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
-	Email     string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	AvatarURL *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
-	IsOnline  bool           `gorm:"default:false" json:"is_online"`
-	LastSeen  *time.Time     `json:"last_seen,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 string         `gorm:"type:varchar(32);primaryKey" json:"id"`
+	Name               string         `gorm:"type:varchar(100);not null" json:"name"`
+	Username           *string        `gorm:"type:varchar(32)" json:"username,omitempty"` // Unique (case-insensitive)
+	UsernameChangedAt  *time.Time     `json:"-"`
+	Email              string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
+	Password           string         `gorm:"type:varchar(255);not null" json:"-"`
+	AvatarURL          *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
+	AvatarAttachmentID *string        `gorm:"type:varchar(32)" json:"-"` // Uploaded picture shown by AvatarURL, if any
+	IsOnline           bool           `gorm:"-" json:"is_online"` // Live presence, loaded from Redis
+	LastSeen           *time.Time     `json:"last_seen,omitempty"`
+	PresenceVisibility string         `gorm:"type:varchar(20);not null;default:'everyone'" json:"-"`
+	DMContactsOnly     bool           `gorm:"column:dm_contacts_only;not null;default:false" json:"-"`
+	PresenceStatus     string         `gorm:"-" json:"presence_status"` // Live presence, loaded from Redis
+	DoNotDisturb       bool           `gorm:"not null;default:false" json:"-"`
+	StatusText         *string        `gorm:"type:varchar(140)" json:"status_text,omitempty"`
+	StatusEmoji        *string        `gorm:"type:varchar(32)" json:"status_emoji,omitempty"`
+	StatusExpiresAt    *time.Time     `json:"status_expires_at,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 	
 	// Relations
 	Devices      []Device      `gorm:"foreignKey:UserID" json:"devices,omitempty"`
@@ -36,4 +53,31 @@ func (user *User) BeforeCreate(tx *gorm.DB) error {
 		user.ID = utils.GenerateID("user")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// HidePresence clears online status, last seen and custom status (for viewers who may not see them)
+func (user *User) HidePresence() {
+	user.IsOnline = false
+	user.LastSeen = nil
+	user.PresenceStatus = PresenceOffline
+	user.StatusText = nil
+	user.StatusEmoji = nil
+	user.StatusExpiresAt = nil
+}
+
+// CanSeePresence checks if the viewer may see this user's online status and last seen
+// isContact tells whether the viewer is a contact of this user
+func (user *User) CanSeePresence(viewerID string, isContact bool) bool {
+	if user.ID == viewerID {
+		return true
+	}
+
+	switch user.PresenceVisibility {
+	case PresenceVisibilityNobody:
+		return false
+	case PresenceVisibilityContacts:
+		return isContact
+	default:
+		return true
+	}
+}
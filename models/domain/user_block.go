@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"chatapp-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserBlock records that one user blocked another
+type UserBlock struct {
+	// Unique ID for this block (blk_xxx)
+	ID string `gorm:"type:varchar(32);primaryKey" json:"id"`
+
+	// Who blocked (FK to users)
+	BlockerID string `gorm:"type:varchar(32);not null" json:"blocker_id"`
+
+	// Who is blocked (FK to users)
+	BlockedID string `gorm:"type:varchar(32);not null" json:"blocked_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Blocked User `gorm:"foreignKey:BlockedID" json:"-"`
+}
+
+// TableName defines the table name in database
+func (block *UserBlock) TableName() string {
+	return "user_blocks"
+}
+
+// BeforeCreate hook to auto-generate ID with "blk_" prefix
+func (block *UserBlock) BeforeCreate(tx *gorm.DB) error {
+	if block.ID == "" {
+		block.ID = utils.GenerateID("blk")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Username limits
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 32
+
+	// UsernameChangeCooldown is how long a user must wait between username changes
+	UsernameChangeCooldown = 30 * 24 * time.Hour
+)
+
+// usernamePattern: starts with a letter, then letters, digits or underscores
+// (no dots/dashes so "@handle," and "@handle." in messages end the mention)
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// reservedUsernames can't be taken by users (mention keywords and staff-like names)
+var reservedUsernames = map[string]bool{
+	"all": true, "here": true, "everyone": true, "channel": true,
+	"me": true, "admin": true, "administrator": true, "root": true, "system": true,
+	"support": true, "help": true, "moderator": true, "owner": true,
+	"staff": true, "official": true, "api": true, "null": true, "undefined": true,
+}
+
+// ValidateUsername checks the format of a username and that it isn't reserved
+func ValidateUsername(username string) error {
+	if len(username) < MinUsernameLength || len(username) > MaxUsernameLength {
+		return errors.New("username must be between 3 and 32 characters")
+	}
+	if !usernamePattern.MatchString(username) {
+		return errors.New("username must start with a letter and contain only letters, digits and underscores")
+	}
+
+	lower := strings.ToLower(username)
+	// "user_" is the prefix of user IDs, which can also be mentioned (@user_xxx)
+	if reservedUsernames[lower] || strings.HasPrefix(lower, "user_") {
+		return errors.New("username is reserved")
+	}
+	return nil
+}
+
+// NextUsernameChangeAt returns when the user may change their username again (nil = now)
+func (user *User) NextUsernameChangeAt() *time.Time {
+	if user.UsernameChangedAt == nil {
+		return nil
+	}
+
+	next := user.UsernameChangedAt.Add(UsernameChangeCooldown)
+	return &next
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantErr  bool
+	}{
+		{name: "valid", username: "alice_42", wantErr: false},
+		{name: "shortest", username: "bob", wantErr: false},
+		{name: "longest", username: "a" + strings.Repeat("b", MaxUsernameLength-1), wantErr: false},
+		{name: "mixed case", username: "AliceSmith", wantErr: false},
+		{name: "too short", username: "ab", wantErr: true},
+		{name: "too long", username: "a" + strings.Repeat("b", MaxUsernameLength), wantErr: true},
+		{name: "starts with a digit", username: "1alice", wantErr: true},
+		{name: "starts with an underscore", username: "_alice", wantErr: true},
+		{name: "dot", username: "alice.smith", wantErr: true},
+		{name: "dash", username: "alice-smith", wantErr: true},
+		{name: "space", username: "alice smith", wantErr: true},
+		{name: "non-ASCII letter", username: "alicé", wantErr: true},
+		{name: "reserved", username: "everyone", wantErr: true},
+		{name: "reserved in another case", username: "Admin", wantErr: true},
+		{name: "user ID prefix", username: "user_01abc", wantErr: true},
+		{name: "user ID prefix in another case", username: "USER_01abc", wantErr: true},
+		{name: "starts like the user ID prefix", username: "username", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUsername(tt.username)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUsername(%q) error = %v, wantErr %v", tt.username, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,7 +10,11 @@ type RegisterRequest struct {
 // UpdateProfileRequest for Updating Profile Body Request
 type UpdateProfileRequest struct {
 	Name      *string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"` // External picture, uploaded ones are set with AvatarID
+	AvatarID *string `json:"avatar_id,omitempty"` // Attachment ID of an image uploaded by the user
+	PresenceVisibility *string `json:"presence_visibility,omitempty" binding:"omitempty,oneof=everyone contacts nobody"`
+	Username *string `json:"username,omitempty" binding:"omitempty,max=33"` // "@" prefix allowed
+	DMContactsOnly *bool `json:"dm_contacts_only,omitempty"` // DMs from non-contacts go to the message request inbox
 }
 
 
@@ -0,0 +1,11 @@
+package web
+
+// SendContactRequest for Sending a Contact Request
+type SendContactRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// GetContactRequestsRequest for Listing Pending Contact Requests (from query string)
+type GetContactRequestsRequest struct {
+	Direction string `form:"direction" binding:"omitempty,oneof=incoming outgoing"` // Default "incoming"
+}
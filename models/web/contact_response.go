@@ -0,0 +1,18 @@
+package web
+
+import "time"
+
+// ContactResponse for a contact of the current user
+type ContactResponse struct {
+	User  UserBriefResponse `json:"user"`
+	Since time.Time         `json:"since"` // When the request was accepted
+}
+
+// ContactRequestResponse for a pending contact request
+type ContactRequestResponse struct {
+	ID        string            `json:"id"`
+	User      UserBriefResponse `json:"user"`      // The other user (requester for incoming, addressee for outgoing)
+	Direction string            `json:"direction"` // "incoming" or "outgoing"
+	Status    string            `json:"status"`    // "pending", or "accepted" when sending answered their request
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -10,7 +10,8 @@ type CreateConversationRequest struct {
 // UpdateConversationRequest for Updating Conversation (rename group)
 type UpdateConversationRequest struct {
     Name      *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
-    AvatarURL *string `json:"avatar_url,omitempty"`
+    AvatarURL *string `json:"avatar_url,omitempty"` // External picture, uploaded ones are set with AvatarID
+    AvatarID  *string `json:"avatar_id,omitempty"`  // Attachment ID of an image uploaded by the user
 }
 
 
@@ -18,3 +19,20 @@ type UpdateConversationRequest struct {
 type AddParticipantRequest struct {
 	UserIDs []string `json:"user_ids" binding:"required,min=1"`
 }
+
+// UpdateParticipantRoleRequest for Changing a Participant's Role
+type UpdateParticipantRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin moderator member"`
+}
+
+// TransferOwnershipRequest for Transferring Group Ownership
+type TransferOwnershipRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// BanParticipantRequest for Banning a User from a Group
+type BanParticipantRequest struct {
+	UserID         string  `json:"user_id" binding:"required"`
+	Reason         *string `json:"reason,omitempty" binding:"omitempty,max=500"`
+	ExpiresInHours *int    `json:"expires_in_hours,omitempty" binding:"omitempty,min=1"` // Empty = permanent
+}
@@ -32,4 +32,13 @@ type ParticipantResponse struct {
 	User UserBriefResponse `json:"user"`
 	Role string `json:"role"`
 	JoinedAt time.Time `json:"joined_at"`
-}
\ No newline at end of file
+}
+
+// BanResponse for Banned User Data
+type BanResponse struct {
+	User      UserBriefResponse `json:"user"`
+	BannedBy  string            `json:"banned_by"`
+	Reason    *string           `json:"reason,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -0,0 +1,8 @@
+package web
+
+// CreateInviteRequest for Creating a Group Invite Link
+type CreateInviteRequest struct {
+	ExpiresInHours  *int `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=8760"` // Empty = never expires
+	MaxUses         *int `json:"max_uses,omitempty" binding:"omitempty,min=1"`                  // Empty = unlimited
+	RequireApproval bool `json:"require_approval"`
+}
@@ -0,0 +1,37 @@
+package web
+
+import "time"
+
+// InviteResponse for Invite Link Data
+type InviteResponse struct {
+	ID              string     `json:"id"`
+	ConversationID  string     `json:"conversation_id"`
+	Code            string     `json:"code"`
+	CreatedBy       string     `json:"created_by"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	MaxUses         *int       `json:"max_uses,omitempty"`
+	UseCount        int        `json:"use_count"`
+	RequireApproval bool       `json:"require_approval"`
+	IsActive        bool       `json:"is_active"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// JoinRequestResponse for Join Request Data
+type JoinRequestResponse struct {
+	ID             string            `json:"id"`
+	ConversationID string            `json:"conversation_id"`
+	User           UserBriefResponse `json:"user"`
+	Status         string            `json:"status"`
+	ReviewedBy     *string           `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// JoinResult for Joining Through an Invite Link
+// Status "joined" comes with the conversation, "pending" with the join request waiting for approval
+type JoinResult struct {
+	Status       string                `json:"status"`
+	Conversation *ConversationResponse `json:"conversation,omitempty"`
+	JoinRequest  *JoinRequestResponse  `json:"join_request,omitempty"`
+}
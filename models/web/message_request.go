@@ -2,10 +2,11 @@ package web
 
 // SendMessageRequest for Sending Message
 type SendMessageRequest struct {
-	ConversationID string  `json:"-"` // Set from URL param, not from body
-	Content        string  `json:"content"`
-	Caption        *string `json:"caption,omitempty"`
-	Type           string  `json:"type" binding:"required,oneof=text image file video audio"`
+	ConversationID string   `json:"-"` // Set from URL param, not from body
+	Content        string   `json:"content"`
+	Caption        *string  `json:"caption,omitempty"`
+	Type           string   `json:"type" binding:"required,oneof=text image file video audio"`
+	AttachmentIDs  []string `json:"attachment_ids,omitempty" binding:"omitempty,max=10,dive,required"` // Uploaded with POST /upload
 }
 
 // UpdateMessageRequest for Updating Message (Optional)
@@ -13,3 +14,10 @@ type UpdateMessageRequest struct {
 	Content *string `json:"content,omitempty" binding:"omitempty,min=1"`
 	Caption *string `json:"caption,omitempty"`
 }
+
+// DeleteMessageRequest for Deleting Message (from query string)
+// Scope "me" hides the message only for the caller, "everyone" leaves a tombstone for all participants
+type DeleteMessageRequest struct {
+	Scope  string  `form:"scope" binding:"omitempty,oneof=me everyone"`
+	Reason *string `form:"reason" binding:"omitempty,max=500"` // Recorded in moderation log when a moderator deletes someone else's message
+}
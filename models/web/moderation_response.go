@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+// ModerationLogResponse for a single moderation log entry
+type ModerationLogResponse struct {
+	ID         string            `json:"id"`
+	Action     string            `json:"action"`
+	Actor      UserBriefResponse `json:"actor"`
+	TargetUser UserBriefResponse `json:"target_user"`
+	MessageID  *string           `json:"message_id,omitempty"`
+	Reason     *string           `json:"reason,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package web
+
+// UploadFileRequest for the optional form fields of POST /upload (the file itself is the "file" field)
+type UploadFileRequest struct {
+	DurationMs   *int `form:"duration_ms" binding:"omitempty,min=0"` // Audio/video duration reported by the client
+	KeepMetadata bool `form:"keep_metadata"`                         // "Send as file": store JPEG/PNG as is, EXIF/GPS included
+}
+
+// GetMediaRequest for GET /media/:id (from query string)
+type GetMediaRequest struct {
+	Thumbnail string `form:"thumbnail" binding:"omitempty,oneof=small medium"` // A thumbnail instead of the original
+	Redirect  bool   `form:"redirect"`                                         // Redirect to the signed URL instead of returning it
+}
+
+// CreateResumableUploadRequest for POST /upload/resumable (tus creation, from headers)
+type CreateResumableUploadRequest struct {
+	Length   int64  `header:"Upload-Length" binding:"required,min=1"` // Total size in bytes (deferred length isn't supported)
+	Metadata string `header:"Upload-Metadata"`                        // "key base64(value),...": filename, duration_ms, keep_metadata
+}
+
+// PatchResumableUploadRequest for PATCH /upload/resumable/:id (tus chunk, from headers)
+type PatchResumableUploadRequest struct {
+	Offset *int64 `header:"Upload-Offset" binding:"required,min=0"` // Must be the number of bytes already received
+}
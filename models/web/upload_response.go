@@ -1,9 +1,56 @@
 package web
 
+import "time"
+
 // UploadResult represents the result of a file upload
+// ID is the attachment ID to send in SendMessageRequest.AttachmentIDs, URL is the media URL (GET /media/:id), not a direct download link
 type UploadResult struct {
-    URL      string `json:"url"`
-    Filename string `json:"filename"`
-    Size     int64  `json:"size"`
-    MimeType string `json:"mime_type"`
+    ID         string              `json:"id"`
+    URL        string              `json:"url"`
+    Filename   string              `json:"filename"`
+    Size       int64               `json:"size"`
+    MimeType   string              `json:"mime_type"`
+    Width      *int                `json:"width,omitempty"`
+    Height     *int                `json:"height,omitempty"`
+    DurationMs *int                `json:"duration_ms,omitempty"`
+    Thumbnails []ThumbnailResponse `json:"thumbnails,omitempty"`
+    Blurhash   *string             `json:"blurhash,omitempty"` // Placeholder to show while the image loads
+    SHA256     string              `json:"sha256"`
+    ScanStatus string              `json:"scan_status"` // "clean", or "failed" when accepted without a working scanner
+}
+
+// ThumbnailResponse for a resized JPEG copy of an image (URL is GET /media/:id?thumbnail=<name>)
+type ThumbnailResponse struct {
+    Name   string `json:"name"` // "small" (320px) or "medium" (1024px), longest side
+    Width  int    `json:"width"`
+    Height int    `json:"height"`
+    URL    string `json:"url"`
+}
+
+// MediaResponse is a short-lived signed download URL for a media file
+type MediaResponse struct {
+    ID        string    `json:"id"`
+    URL       string    `json:"url"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResumableUploadResponse is the state of a resumable (tus) upload
+// Attachment is set once all bytes were received, its ID is what to send in SendMessageRequest.AttachmentIDs
+type ResumableUploadResponse struct {
+    ID         string        `json:"id"`
+    Filename   string        `json:"filename"`
+    Length     int64         `json:"length"`
+    Offset     int64         `json:"offset"`
+    ExpiresAt  time.Time     `json:"expires_at"`
+    Attachment *UploadResult `json:"attachment,omitempty"`
+}
+
+// StorageUsageResponse for GET /auth/me/storage
+// Limits are null when unlimited
+type StorageUsageResponse struct {
+    UsedBytes       int64  `json:"used_bytes"` // Total size of the user's attachments
+    QuotaBytes      *int64 `json:"quota_bytes"`
+    RemainingBytes  *int64 `json:"remaining_bytes"`
+    UploadsLastHour int64  `json:"uploads_last_hour"`
+    UploadsPerHour  *int   `json:"uploads_per_hour"`
 }
@@ -0,0 +1,10 @@
+package web
+
+// UpdatePresenceRequest for Updating DND and Custom Status
+type UpdatePresenceRequest struct {
+	Status           *string `json:"status,omitempty" binding:"omitempty,oneof=online dnd"` // "dnd" turns do-not-disturb on, "online" turns it off
+	StatusText       *string `json:"status_text,omitempty" binding:"omitempty,max=140"`
+	StatusEmoji      *string `json:"status_emoji,omitempty" binding:"omitempty,max=32"`
+	ExpiresInMinutes *int    `json:"expires_in_minutes,omitempty" binding:"omitempty,min=1,max=10080"` // Max 7 days
+	ClearStatus      bool    `json:"clear_status"`
+}
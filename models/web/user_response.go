@@ -1,15 +1,25 @@
 package web
 
-import "time"
+import (
+	"chatapp-api/models/domain"
+	"time"
+)
 
 // UserResponse for User Data Sent to the Client (with no password)
 type UserResponse struct {
 	ID string `json:"id"`
 	Name string `json:"name"`
+	Username *string `json:"username,omitempty"`
 	Email string `json:"email"`
 	AvatarURL *string `json:"avatar_url,omitempty"`
 	IsOnline bool `json:"is_online"`
 	LastSeen *time.Time `json:"last_seen,omitempty"`
+	PresenceVisibility string `json:"presence_visibility,omitempty"` // Only filled for the user themself
+	DMContactsOnly bool `json:"dm_contacts_only"`
+	Status string `json:"status"` // "online", "idle", "dnd" or "offline"
+	StatusText *string `json:"status_text,omitempty"`
+	StatusEmoji *string `json:"status_emoji,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -18,6 +28,61 @@ type UserResponse struct {
 type UserBriefResponse struct {
 	ID string `json:"id"`
 	Name string `json:"name"`
+	Username *string `json:"username,omitempty"`
 	AvatarURL *string `json:"avatar_url,omitempty"`
 	IsOnline bool `json:"is_online"`
+	Status string `json:"status,omitempty"` // "online", "idle", "dnd" or "offline"
+	StatusText *string `json:"status_text,omitempty"`
+	StatusEmoji *string `json:"status_emoji,omitempty"`
+}
+
+// NewUserBrief converts a user to the brief shown to viewerID
+// Presence (online state and custom status) is only filled if the viewer may see it
+// isContact tells whether the viewer is a contact of the user
+func NewUserBrief(user *domain.User, viewerID string, isContact bool) UserBriefResponse {
+	brief := UserBriefResponse{
+		ID: user.ID,
+		Name: user.Name,
+		Username: user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+
+	if user.CanSeePresence(viewerID, isContact) {
+		brief.IsOnline = user.IsOnline
+		brief.Status = user.PresenceStatus
+		brief.StatusText, brief.StatusEmoji = user.ActiveCustomStatus(time.Now())
+	}
+
+	return brief
+}
+
+// BlockedUserResponse for a user in the current user's block list
+type BlockedUserResponse struct {
+	User      UserBriefResponse `json:"user"`
+	BlockedAt time.Time         `json:"blocked_at"`
+}
+
+
+// PresenceResponse for the current user's presence and custom status
+type PresenceResponse struct {
+	Status          string     `json:"status"`
+	DoNotDisturb    bool       `json:"do_not_disturb"`
+	StatusText      *string    `json:"status_text,omitempty"`
+	StatusEmoji     *string    `json:"status_emoji,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+}
+
+// PublicProfileResponse for Another User's Profile (no email, presence only if they share it with you)
+type PublicProfileResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Username    *string    `json:"username,omitempty"`
+	AvatarURL   *string    `json:"avatar_url,omitempty"`
+	IsOnline    bool       `json:"is_online"`
+	Status      string     `json:"status,omitempty"`
+	StatusText  *string    `json:"status_text,omitempty"`
+	StatusEmoji *string    `json:"status_emoji,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	IsBlocked   bool       `json:"is_blocked"` // Blocked by you
+	CreatedAt   time.Time  `json:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+package attachment
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+)
+
+// AttachmentRepository interface for attachment (uploaded file) operations
+type AttachmentRepository interface {
+	// Create saves a new attachment
+	Create(ctx context.Context, attachment *domain.Attachment) error
+
+	// FindByID finds an attachment by ID
+	FindByID(ctx context.Context, id string) (*domain.Attachment, error)
+
+	// FindByIDs finds attachments by IDs (missing IDs are skipped)
+	FindByIDs(ctx context.Context, ids []string) ([]domain.Attachment, error)
+
+	// AttachToMessage links the uploader's unsent attachments to a message, in the given order (in one transaction)
+	// Returns gorm.ErrRecordNotFound if one of them isn't an unsent attachment of the uploader
+	AttachToMessage(ctx context.Context, ids []string, messageID, uploaderID string) error
+
+	// DetachFromMessage unlinks all attachments of a message (e.g. deleted for everyone)
+	DetachFromMessage(ctx context.Context, messageID string) error
+
+	// IsVisibleToUser reports whether an attachment was sent in a conversation the user participates in
+	// (messages deleted for everyone don't count)
+	IsVisibleToUser(ctx context.Context, id, userID string) (bool, error)
+
+	// SumSizeByUploader returns the total size in bytes of the user's attachments
+	SumSizeByUploader(ctx context.Context, uploaderID string) (int64, error)
+
+	// CountByUploaderSince counts the attachments the user uploaded after the given time
+	CountByUploaderSince(ctx context.Context, uploaderID string, since time.Time) (int64, error)
+
+	// FindOrphaned finds attachments created before the given time that nothing uses: never sent or sent with a deleted
+	// message, and not the attachment of a profile picture or a group avatar.
+	// Ordered by ID, starting after afterID (empty = from the start)
+	FindOrphaned(ctx context.Context, createdBefore time.Time, afterID string, limit int) ([]domain.Attachment, error)
+
+	// DeleteIfOrphaned deletes an attachment if it is still orphaned (e.g. it wasn't sent meanwhile).
+	// Returns whether it was deleted
+	DeleteIfOrphaned(ctx context.Context, id string, createdBefore time.Time) (bool, error)
+}
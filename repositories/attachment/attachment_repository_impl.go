@@ -0,0 +1,172 @@
+package attachment
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// attachmentRepositoryImpl implements AttachmentRepository
+type attachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepositoryImpl{db: db}
+}
+
+// Create implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) Create(ctx context.Context, attachment *domain.Attachment) error {
+	return repo.db.WithContext(ctx).Omit(clause.Associations).Create(attachment).Error
+}
+
+// FindByID implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+
+	err := repo.db.WithContext(ctx).
+	Where("id = ?", id).
+	First(&attachment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// FindByIDs implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) FindByIDs(ctx context.Context, ids []string) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+
+	err := repo.db.WithContext(ctx).
+	Where("id IN ?", ids).
+	Find(&attachments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// AttachToMessage implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) AttachToMessage(ctx context.Context, ids []string, messageID, uploaderID string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			// Only an unsent attachment of the uploader can be linked (guards against sending it twice)
+			result := tx.Model(&domain.Attachment{}).
+				Where("id = ? AND uploader_id = ? AND message_id IS NULL", id, uploaderID).
+				Updates(map[string]interface{}{"message_id": messageID, "position": position})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// DetachFromMessage implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) DetachFromMessage(ctx context.Context, messageID string) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.Attachment{}).
+	Where("message_id = ?", messageID).
+	Update("message_id", nil).Error
+}
+
+// IsVisibleToUser implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) IsVisibleToUser(ctx context.Context, id, userID string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Attachment{}).
+	Joins("JOIN messages ON messages.id = attachments.message_id AND messages.is_deleted = false AND messages.deleted_at IS NULL").
+	Joins("JOIN participants ON participants.conversation_id = messages.conversation_id AND participants.user_id = ?", userID).
+	Where("attachments.id = ?", id).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SumSizeByUploader implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) SumSizeByUploader(ctx context.Context, uploaderID string) (int64, error) {
+	var total int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Attachment{}).
+	Select("COALESCE(SUM(size), 0)").
+	Where("uploader_id = ?", uploaderID).
+	Scan(&total).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// CountByUploaderSince implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) CountByUploaderSince(ctx context.Context, uploaderID string, since time.Time) (int64, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Attachment{}).
+	Where("uploader_id = ? AND created_at > ?", uploaderID, since).
+	Count(&count).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindOrphaned implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) FindOrphaned(ctx context.Context, createdBefore time.Time, afterID string, limit int) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+
+	err := whereOrphaned(repo.db.WithContext(ctx), createdBefore).
+	Where("attachments.id > ?", afterID).
+	Order("attachments.id ASC").
+	Limit(limit).
+	Find(&attachments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// DeleteIfOrphaned implements AttachmentRepository
+func (repo *attachmentRepositoryImpl) DeleteIfOrphaned(ctx context.Context, id string, createdBefore time.Time) (bool, error) {
+	result := whereOrphaned(repo.db.WithContext(ctx), createdBefore).
+	Where("attachments.id = ?", id).
+	Delete(&domain.Attachment{})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// whereOrphaned filters attachments created before the given time that no message, profile picture or group avatar uses
+func whereOrphaned(db *gorm.DB, createdBefore time.Time) *gorm.DB {
+	return db.
+	Where("attachments.created_at < ?", createdBefore).
+	Where("attachments.message_id IS NULL OR EXISTS (SELECT 1 FROM messages WHERE messages.id = attachments.message_id AND (messages.is_deleted = true OR messages.deleted_at IS NOT NULL))").
+	Where("NOT EXISTS (SELECT 1 FROM users WHERE users.avatar_attachment_id = attachments.id)").
+	Where("NOT EXISTS (SELECT 1 FROM conversations WHERE conversations.avatar_attachment_id = attachments.id)")
+}
@@ -0,0 +1,22 @@
+package ban
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// BanRepository interface for conversation ban operations
+type BanRepository interface {
+	// Upsert bans a user, or updates the existing ban (reason, expiry, banned by)
+	Upsert(ctx context.Context, ban *domain.ConversationBan) error
+
+	// FindActive finds the active (not expired) ban of a user in a conversation
+	FindActive(ctx context.Context, conversationID, userID string) (*domain.ConversationBan, error)
+
+	// FindActiveByConversationID finds all active bans of a conversation (newest first)
+	FindActiveByConversationID(ctx context.Context, conversationID string) ([]domain.ConversationBan, error)
+
+	// Delete lifts the ban of a user in a conversation
+	// Returns false if the user wasn't banned
+	Delete(ctx context.Context, conversationID, userID string) (bool, error)
+}
@@ -0,0 +1,75 @@
+package ban
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// banRepositoryImpl implements BanRepository
+type banRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBanRepository creates a new ban repository
+func NewBanRepository(db *gorm.DB) BanRepository {
+	return &banRepositoryImpl{db: db}
+}
+
+// Upsert implements BanRepository
+func (repo *banRepositoryImpl) Upsert(ctx context.Context, ban *domain.ConversationBan) error {
+	return repo.db.WithContext(ctx).
+	Omit(clause.Associations).
+	Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "conversation_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"banned_by", "reason", "expires_at", "created_at"}),
+	}).
+	Create(ban).Error
+}
+
+// FindActive implements BanRepository
+func (repo *banRepositoryImpl) FindActive(ctx context.Context, conversationID, userID string) (*domain.ConversationBan, error) {
+	var ban domain.ConversationBan
+	err := repo.db.WithContext(ctx).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		First(&ban).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// FindActiveByConversationID implements BanRepository
+func (repo *banRepositoryImpl) FindActiveByConversationID(ctx context.Context, conversationID string) ([]domain.ConversationBan, error) {
+	var bans []domain.ConversationBan
+
+	err := repo.db.WithContext(ctx).
+	Preload("User").
+	Where("conversation_id = ?", conversationID).
+	Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+	Order("created_at DESC").
+	Find(&bans).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// Delete implements BanRepository
+func (repo *banRepositoryImpl) Delete(ctx context.Context, conversationID, userID string) (bool, error) {
+	result := repo.db.WithContext(ctx).
+	Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+	Delete(&domain.ConversationBan{})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,45 @@
+package contact
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// ContactRepository interface for contact operations
+type ContactRepository interface {
+	// Create saves a new contact request
+	Create(ctx context.Context, contact *domain.Contact) error
+
+	// FindByID finds a contact by ID (with both users)
+	FindByID(ctx context.Context, id string) (*domain.Contact, error)
+
+	// FindBetween finds the contact (pending or accepted) between two users, whoever sent the request
+	FindBetween(ctx context.Context, userID1, userID2 string) (*domain.Contact, error)
+
+	// Accept marks a pending request as accepted
+	Accept(ctx context.Context, contact *domain.Contact) error
+
+	// Delete removes a contact or request (declined, cancelled or removed)
+	Delete(ctx context.Context, id string) error
+
+	// DeleteBetween removes any contact or request between two users (e.g. after a block)
+	DeleteBetween(ctx context.Context, userID1, userID2 string) error
+
+	// FindAccepted finds the contacts of a user (newest first, with both users)
+	FindAccepted(ctx context.Context, userID string, limit, offset int) ([]domain.Contact, int64, error)
+
+	// FindPendingIncoming finds requests received by a user (newest first, with requester)
+	FindPendingIncoming(ctx context.Context, userID string) ([]domain.Contact, error)
+
+	// FindPendingOutgoing finds requests sent by a user (newest first, with addressee)
+	FindPendingOutgoing(ctx context.Context, userID string) ([]domain.Contact, error)
+
+	// FindContactIDs returns the user IDs of all accepted contacts of a user
+	FindContactIDs(ctx context.Context, userID string) ([]string, error)
+
+	// FindAcceptedAmong finds the accepted contacts between users of the given list (without users)
+	FindAcceptedAmong(ctx context.Context, userIDs []string) ([]domain.Contact, error)
+
+	// AreContacts checks whether two users are accepted contacts
+	AreContacts(ctx context.Context, userID1, userID2 string) (bool, error)
+}
@@ -0,0 +1,208 @@
+package contact
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// contactRepositoryImpl implements ContactRepository
+type contactRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(db *gorm.DB) ContactRepository {
+	return &contactRepositoryImpl{db: db}
+}
+
+// betweenUsers matches the contact row of two users in either direction
+const betweenUsers = "(requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)"
+
+// Create implements ContactRepository
+func (repo *contactRepositoryImpl) Create(ctx context.Context, contact *domain.Contact) error {
+	return repo.db.WithContext(ctx).
+	Omit(clause.Associations).
+	Create(contact).Error
+}
+
+// FindByID implements ContactRepository
+func (repo *contactRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.Contact, error) {
+	var contact domain.Contact
+
+	err := repo.db.WithContext(ctx).
+	Preload("Requester").
+	Preload("Addressee").
+	Where("id = ?", id).
+	First(&contact).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+// FindBetween implements ContactRepository
+func (repo *contactRepositoryImpl) FindBetween(ctx context.Context, userID1, userID2 string) (*domain.Contact, error) {
+	var contact domain.Contact
+
+	err := repo.db.WithContext(ctx).
+	Where(betweenUsers, userID1, userID2, userID2, userID1).
+	First(&contact).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+// Accept implements ContactRepository
+func (repo *contactRepositoryImpl) Accept(ctx context.Context, contact *domain.Contact) error {
+	now := time.Now()
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Contact{}).
+	Where("id = ?", contact.ID).
+	Updates(map[string]interface{}{
+		"status": domain.ContactStatusAccepted,
+		"accepted_at": now,
+	}).Error
+
+	if err != nil {
+		return err
+	}
+
+	contact.Status = domain.ContactStatusAccepted
+	contact.AcceptedAt = &now
+	return nil
+}
+
+// Delete implements ContactRepository
+func (repo *contactRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).Delete(&domain.Contact{}, "id = ?", id).Error
+}
+
+// DeleteBetween implements ContactRepository
+func (repo *contactRepositoryImpl) DeleteBetween(ctx context.Context, userID1, userID2 string) error {
+	return repo.db.WithContext(ctx).
+	Where(betweenUsers, userID1, userID2, userID2, userID1).
+	Delete(&domain.Contact{}).Error
+}
+
+// FindAccepted implements ContactRepository
+func (repo *contactRepositoryImpl) FindAccepted(ctx context.Context, userID string, limit, offset int) ([]domain.Contact, int64, error) {
+	query := repo.db.WithContext(ctx).
+	Model(&domain.Contact{}).
+	Where("status = ?", domain.ContactStatusAccepted).
+	Where("requester_id = ? OR addressee_id = ?", userID, userID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var contacts []domain.Contact
+	err := query.
+	Preload("Requester").
+	Preload("Addressee").
+	Order("accepted_at DESC").
+	Limit(limit).
+	Offset(offset).
+	Find(&contacts).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contacts, total, nil
+}
+
+// FindPendingIncoming implements ContactRepository
+func (repo *contactRepositoryImpl) FindPendingIncoming(ctx context.Context, userID string) ([]domain.Contact, error) {
+	var contacts []domain.Contact
+
+	err := repo.db.WithContext(ctx).
+	Preload("Requester").
+	Where("addressee_id = ? AND status = ?", userID, domain.ContactStatusPending).
+	Order("created_at DESC").
+	Find(&contacts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// FindPendingOutgoing implements ContactRepository
+func (repo *contactRepositoryImpl) FindPendingOutgoing(ctx context.Context, userID string) ([]domain.Contact, error) {
+	var contacts []domain.Contact
+
+	err := repo.db.WithContext(ctx).
+	Preload("Addressee").
+	Where("requester_id = ? AND status = ?", userID, domain.ContactStatusPending).
+	Order("created_at DESC").
+	Find(&contacts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// FindContactIDs implements ContactRepository
+func (repo *contactRepositoryImpl) FindContactIDs(ctx context.Context, userID string) ([]string, error) {
+	var userIDs []string
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Contact{}).
+	Where("status = ?", domain.ContactStatusAccepted).
+	Where("requester_id = ? OR addressee_id = ?", userID, userID).
+	Select("CASE WHEN requester_id = ? THEN addressee_id ELSE requester_id END", userID).
+	Scan(&userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// FindAcceptedAmong implements ContactRepository
+func (repo *contactRepositoryImpl) FindAcceptedAmong(ctx context.Context, userIDs []string) ([]domain.Contact, error) {
+	var contacts []domain.Contact
+
+	err := repo.db.WithContext(ctx).
+	Where("status = ?", domain.ContactStatusAccepted).
+	Where("requester_id IN ? AND addressee_id IN ?", userIDs, userIDs).
+	Find(&contacts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// AreContacts implements ContactRepository
+func (repo *contactRepositoryImpl) AreContacts(ctx context.Context, userID1, userID2 string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Contact{}).
+	Where("status = ?", domain.ContactStatusAccepted).
+	Where(betweenUsers, userID1, userID2, userID2, userID1).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -13,12 +13,18 @@ type ConversationRepository interface {
 	// FingByID finds a conversation by ID
 	FindByID(ctx context.Context, id string) (*domain.Conversation, error)
 
-	// FindByUserID finds all conversations for a user
+	// FindByUserID finds all conversations for a user (without DMs waiting in their message request inbox)
 	FindByUserID(ctx context.Context, userID string) ([]domain.Conversation, error)
 
+	// FindMessageRequests finds the DMs waiting in a user's message request inbox
+	FindMessageRequests(ctx context.Context, userID string) ([]domain.Conversation, error)
+
 	// FindDirectConversation finds existing DM between two users
 	FindDirectConversation(ctx context.Context, userID1, userID2 string) (*domain.Conversation, error)
 
+	// FindPeerUserIDs finds all users that share at least one conversation with the user
+	FindPeerUserIDs(ctx context.Context, userID string) ([]string, error)
+
 	// Update updates a conversation
 	Update(ctx context.Context, conv *domain.Conversation) error
 
@@ -29,6 +35,21 @@ type ConversationRepository interface {
 	// AddParticipant adds new participant to conversation 
 	AddParticipant(ctx context.Context, participant *domain.Participant) error
 
+	// AddParticipants adds several participants to conversations in one statement: all of them or none
+	AddParticipants(ctx context.Context, participants []domain.Participant) error
+
 	// RemoveParticipant removes participant from conversation
 	RemoveParticipant(ctx context.Context, conversationID, userID string) error
+
+	// SetMessageRequest moves a DM into (true) or out of (false) the participant's message request inbox
+	SetMessageRequest(ctx context.Context, conversationID, userID string, isRequest bool) error
+
+	// UpdateParticipantRole changes the role of a participant
+	UpdateParticipantRole(ctx context.Context, conversationID, userID, role string) error
+
+	// TransferOwnership makes toUserID the owner and demotes fromUserID to admin (in one transaction)
+	TransferOwnership(ctx context.Context, conversationID, fromUserID, toUserID string) error
+
+	// IsAvatarVisibleToUser reports whether the attachment is the picture of a conversation the user participates in
+	IsAvatarVisibleToUser(ctx context.Context, attachmentID, userID string) (bool, error)
 }
\ No newline at end of file
@@ -40,15 +40,27 @@ func (repo *conversationRepositoryImpl) FindByID(ctx context.Context, id string)
 
 // FindByUserID implements ConversationRepository
 func (repo *conversationRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]domain.Conversation, error) {
+	return repo.findByParticipant(ctx, userID, false)
+}
+
+// FindMessageRequests implements ConversationRepository
+func (repo *conversationRepositoryImpl) FindMessageRequests(ctx context.Context, userID string) ([]domain.Conversation, error) {
+	return repo.findByParticipant(ctx, userID, true)
+}
+
+// findByParticipant finds the conversations of a user, either in their message request inbox or not
+func (repo *conversationRepositoryImpl) findByParticipant(ctx context.Context, userID string, isRequest bool) ([]domain.Conversation, error) {
 	var conversations []domain.Conversation
 
 	err := repo.db.WithContext(ctx).
 	Joins("JOIN participants ON participants.conversation_id = conversations.id").
-	Where("participants.user_id = ?", userID).
+	Where("participants.user_id = ? AND participants.is_message_request = ?", userID, isRequest).
 	Preload("Participants").
 	Preload("Participants.User").
 	Preload("Messages", func(db *gorm.DB) *gorm.DB {
-		return db.Order("created_at DESC").Limit(1)
+		// Last message, unless the user deleted it "for me"
+		return db.Where("id NOT IN (SELECT message_id FROM hidden_messages WHERE user_id = ?)", userID).
+		Order("created_at DESC").Limit(1)
 	}).
 	Order("updated_at DESC").
 	Find(&conversations).Error
@@ -78,6 +90,25 @@ func (repo *conversationRepositoryImpl) FindDirectConversation(ctx context.Conte
 
 }
 
+// FindPeerUserIDs implements ConversationRepository
+func (repo *conversationRepositoryImpl) FindPeerUserIDs(ctx context.Context, userID string) ([]string, error) {
+	var userIDs []string
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Participant{}).
+	Distinct("participants.user_id").
+	Joins("JOIN participants me ON me.conversation_id = participants.conversation_id AND me.user_id = ?", userID).
+	Joins("JOIN conversations ON conversations.id = participants.conversation_id AND conversations.deleted_at IS NULL").
+	Where("participants.user_id <> ?", userID).
+	Pluck("participants.user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 // Update implements ConversationRepository
 func (repo *conversationRepositoryImpl) Update(ctx context.Context, conv *domain.Conversation) error {
 	return repo.db.WithContext(ctx).Save(conv).Error
@@ -93,9 +124,64 @@ func (repo *conversationRepositoryImpl) AddParticipant(ctx context.Context, part
 	return repo.db.WithContext(ctx).Create(participant).Error
 }
 
+// AddParticipants implements ConversationRepository
+func (repo *conversationRepositoryImpl) AddParticipants(ctx context.Context, participants []domain.Participant) error {
+	return repo.db.WithContext(ctx).Create(&participants).Error
+}
+
+// SetMessageRequest implements ConversationRepository
+func (repo *conversationRepositoryImpl) SetMessageRequest(ctx context.Context, conversationID, userID string, isRequest bool) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.Participant{}).
+	Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+	Update("is_message_request", isRequest).Error
+}
+
 // RemoveParticipant removes a participant from a conversation
 func (repo *conversationRepositoryImpl) RemoveParticipant(ctx context.Context, conversationID, userID string) error {
     return repo.db.WithContext(ctx).
         Where("conversation_id = ? AND user_id = ?", conversationID, userID).
         Delete(&domain.Participant{}).Error
-}
\ No newline at end of file
+}
+
+// UpdateParticipantRole implements ConversationRepository
+func (repo *conversationRepositoryImpl) UpdateParticipantRole(ctx context.Context, conversationID, userID, role string) error {
+	return repo.db.WithContext(ctx).
+		Model(&domain.Participant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Update("role", role).Error
+}
+
+// TransferOwnership implements ConversationRepository
+func (repo *conversationRepositoryImpl) TransferOwnership(ctx context.Context, conversationID, fromUserID, toUserID string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Demote current owner to admin
+		if err := tx.Model(&domain.Participant{}).
+			Where("conversation_id = ? AND user_id = ?", conversationID, fromUserID).
+			Update("role", domain.RoleAdmin).Error; err != nil {
+			return err
+		}
+
+		// 2. Promote new owner
+		return tx.Model(&domain.Participant{}).
+			Where("conversation_id = ? AND user_id = ?", conversationID, toUserID).
+			Update("role", domain.RoleOwner).Error
+	})
+}
+
+// IsAvatarVisibleToUser implements ConversationRepository
+func (repo *conversationRepositoryImpl) IsAvatarVisibleToUser(ctx context.Context, attachmentID, userID string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.Conversation{}).
+	Joins("JOIN participants ON participants.conversation_id = conversations.id AND participants.user_id = ?", userID).
+	Where("conversations.avatar_attachment_id = ?", attachmentID).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
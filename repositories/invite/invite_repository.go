@@ -0,0 +1,35 @@
+package invite
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// InviteRepository interface for conversation invite operations
+type InviteRepository interface {
+	// Create creates a new invite
+	Create(ctx context.Context, invite *domain.ConversationInvite) error
+
+	// FindByID finds an invite by ID
+	FindByID(ctx context.Context, id string) (*domain.ConversationInvite, error)
+
+	// FindByCode finds an invite by its shareable code
+	FindByCode(ctx context.Context, code string) (*domain.ConversationInvite, error)
+
+	// FindByConversationID finds all invites of a conversation (newest first)
+	FindByConversationID(ctx context.Context, conversationID string) ([]domain.ConversationInvite, error)
+
+	// Revoke marks an invite as revoked
+	Revoke(ctx context.Context, id string) error
+
+	// IncrementUseCount uses the invite once
+	// Returns false if the invite was revoked, expired or reached its max uses in the meantime
+	IncrementUseCount(ctx context.Context, id string) (bool, error)
+
+	// DecrementUseCount gives back a use taken by a join that failed afterwards
+	DecrementUseCount(ctx context.Context, id string) error
+
+	// UseForJoinRequest uses the invite once and creates the join request in one transaction
+	// Returns false, without creating the request, if the invite can't be used anymore
+	UseForJoinRequest(ctx context.Context, request *domain.JoinRequest) (bool, error)
+}
@@ -0,0 +1,119 @@
+package invite
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// inviteRepositoryImpl implements InviteRepository
+type inviteRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new invite repository
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepositoryImpl{db: db}
+}
+
+// Create implements InviteRepository
+func (repo *inviteRepositoryImpl) Create(ctx context.Context, invite *domain.ConversationInvite) error {
+	return repo.db.WithContext(ctx).Create(invite).Error
+}
+
+// FindByID implements InviteRepository
+func (repo *inviteRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.ConversationInvite, error) {
+	var invite domain.ConversationInvite
+	err := repo.db.WithContext(ctx).Where("id = ?", id).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// FindByCode implements InviteRepository
+func (repo *inviteRepositoryImpl) FindByCode(ctx context.Context, code string) (*domain.ConversationInvite, error) {
+	var invite domain.ConversationInvite
+	err := repo.db.WithContext(ctx).Where("code = ?", code).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// FindByConversationID implements InviteRepository
+func (repo *inviteRepositoryImpl) FindByConversationID(ctx context.Context, conversationID string) ([]domain.ConversationInvite, error) {
+	var invites []domain.ConversationInvite
+
+	err := repo.db.WithContext(ctx).
+	Where("conversation_id = ?", conversationID).
+	Order("created_at DESC").
+	Find(&invites).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// Revoke implements InviteRepository
+func (repo *inviteRepositoryImpl) Revoke(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.ConversationInvite{}).
+	Where("id = ? AND revoked_at IS NULL", id).
+	Update("revoked_at", time.Now()).Error
+}
+
+// IncrementUseCount implements InviteRepository
+func (repo *inviteRepositoryImpl) IncrementUseCount(ctx context.Context, id string) (bool, error) {
+	return incrementUseCount(repo.db.WithContext(ctx), id)
+}
+
+// DecrementUseCount implements InviteRepository
+func (repo *inviteRepositoryImpl) DecrementUseCount(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.ConversationInvite{}).
+	Where("id = ? AND use_count > 0", id).
+	Update("use_count", gorm.Expr("use_count - 1")).Error
+}
+
+// UseForJoinRequest implements InviteRepository
+func (repo *inviteRepositoryImpl) UseForJoinRequest(ctx context.Context, request *domain.JoinRequest) (bool, error) {
+	var used bool
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Take a use of the invite
+		var err error
+		used, err = incrementUseCount(tx, request.InviteID)
+		if err != nil || !used {
+			return err
+		}
+
+		// 2. Record the request, a failure gives the use back
+		return tx.Create(request).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return used, nil
+}
+
+// incrementUseCount takes a use of the invite if it is still usable
+// Single conditional UPDATE so two users can't both take the last use, nor use an invite revoked or expired meanwhile
+func incrementUseCount(db *gorm.DB, id string) (bool, error) {
+	result := db.
+	Model(&domain.ConversationInvite{}).
+	Where("id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", id, time.Now()).
+	Where("max_uses IS NULL OR use_count < max_uses").
+	Update("use_count", gorm.Expr("use_count + 1"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,24 @@
+package join_request
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// JoinRequestRepository interface for join request operations
+type JoinRequestRepository interface {
+	// Create creates a new join request
+	Create(ctx context.Context, request *domain.JoinRequest) error
+
+	// FindByID finds a join request by ID (with user info)
+	FindByID(ctx context.Context, id string) (*domain.JoinRequest, error)
+
+	// FindPending finds the pending join request of a user for a conversation
+	FindPending(ctx context.Context, conversationID, userID string) (*domain.JoinRequest, error)
+
+	// FindPendingByConversationID finds all pending join requests of a conversation (oldest first)
+	FindPendingByConversationID(ctx context.Context, conversationID string) ([]domain.JoinRequest, error)
+
+	// Update updates a join request
+	Update(ctx context.Context, request *domain.JoinRequest) error
+}
@@ -0,0 +1,69 @@
+package join_request
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// joinRequestRepositoryImpl implements JoinRequestRepository
+type joinRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewJoinRequestRepository creates a new join request repository
+func NewJoinRequestRepository(db *gorm.DB) JoinRequestRepository {
+	return &joinRequestRepositoryImpl{db: db}
+}
+
+// Create implements JoinRequestRepository
+func (repo *joinRequestRepositoryImpl) Create(ctx context.Context, request *domain.JoinRequest) error {
+	return repo.db.WithContext(ctx).Create(request).Error
+}
+
+// FindByID implements JoinRequestRepository
+func (repo *joinRequestRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.JoinRequest, error) {
+	var request domain.JoinRequest
+	err := repo.db.WithContext(ctx).Preload("User").Where("id = ?", id).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPending implements JoinRequestRepository
+func (repo *joinRequestRepositoryImpl) FindPending(ctx context.Context, conversationID, userID string) (*domain.JoinRequest, error) {
+	var request domain.JoinRequest
+	err := repo.db.WithContext(ctx).
+		Preload("User").
+		Where("conversation_id = ? AND user_id = ? AND status = ?", conversationID, userID, domain.JoinRequestPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPendingByConversationID implements JoinRequestRepository
+func (repo *joinRequestRepositoryImpl) FindPendingByConversationID(ctx context.Context, conversationID string) ([]domain.JoinRequest, error) {
+	var requests []domain.JoinRequest
+
+	err := repo.db.WithContext(ctx).
+	Preload("User").
+	Where("conversation_id = ? AND status = ?", conversationID, domain.JoinRequestPending).
+	Order("created_at ASC").
+	Find(&requests).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// Update implements JoinRequestRepository
+func (repo *joinRequestRepositoryImpl) Update(ctx context.Context, request *domain.JoinRequest) error {
+	return repo.db.WithContext(ctx).Omit(clause.Associations).Save(request).Error
+}
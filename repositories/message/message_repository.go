@@ -18,16 +18,24 @@ type MessageRepository interface {
 	FindByConversationID(ctx context.Context, conversationID string, limit, offset int) ([]domain.Message, error)
 
 	// FindByConversationIDWithCursor finds messages using cursor-based pagination
-	FindByConversationIDWithCursor(ctx context.Context, conversationID string, cursor *time.Time, limit int) ([]domain.Message, error)
+	// Messages hidden by the user ("delete for me") are excluded
+	FindByConversationIDWithCursor(ctx context.Context, conversationID, userID string, cursor *time.Time, limit int) ([]domain.Message, error)
+
 	// Update updates a message
 	Update(ctx context.Context, message *domain.Message) error
 
 	// Delete deletes a message
 	Delete(ctx context.Context, id string) error
 
-	// CountByConversationID counts messages in a conversation
-	CountByConversationID(ctx context.Context, conversationID string) (int64, error)
+	// HideForUser hides a message only for one user ("delete for me")
+	HideForUser(ctx context.Context, messageID, userID string) error
+
+	// IsHiddenForUser checks whether the user hid the message ("delete for me")
+	IsHiddenForUser(ctx context.Context, messageID, userID string) (bool, error)
+
+	// CountByConversationID counts messages in a conversation, except those the user deleted "for me"
+	CountByConversationID(ctx context.Context, conversationID, userID string) (int64, error)
 
-	// FindLastByConversationID finds the last message in a conversation
-	FindLastByConversationID(ctx context.Context, conversationID string) (*domain.Message, error)
+	// FindLastByConversationID finds the last message in a conversation the user didn't delete "for me"
+	FindLastByConversationID(ctx context.Context, conversationID, userID string) (*domain.Message, error)
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // messageRepositoryImpl is an implementation of MessageRepository interface
@@ -18,6 +19,11 @@ func NewMessageRepository(db *gorm.DB) MessageRepository {
 	return &messageRepositoryImpl{db: db}
 }
 
+// orderAttachments preloads a message's attachments in the order they were sent
+func orderAttachments(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
 // Create implements MessageRepository
 func (repo *messageRepositoryImpl) Create(ctx context.Context, message *domain.Message) error {
 	return repo.db.WithContext(ctx).Create(message).Error
@@ -27,6 +33,7 @@ func (repo *messageRepositoryImpl) Create(ctx context.Context, message *domain.M
 func (repo *messageRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.Message, error) {
 	var message domain.Message
 	err := repo.db.WithContext(ctx).Preload("Sender").
+	Preload("Attachments", orderAttachments).
 	Where("id = ?", id).First(&message).Error
 
 	if err != nil {
@@ -42,6 +49,7 @@ func (repo *messageRepositoryImpl) FindByConversationID(ctx context.Context, con
 
 	err := repo.db.WithContext(ctx).
 	Preload("Sender").
+	Preload("Attachments", orderAttachments).
 	Where("conversation_id = ?", conversationID).
 	Order("created_at DESC").
 	Limit(limit).
@@ -56,12 +64,14 @@ func (repo *messageRepositoryImpl) FindByConversationID(ctx context.Context, con
 }
 
 // FindByConversationIDWithCursor implements MessageRepository
-func (repo *messageRepositoryImpl) FindByConversationIDWithCursor(ctx context.Context, conversationID string, cursor *time.Time, limit int) ([]domain.Message, error) {
+func (repo *messageRepositoryImpl) FindByConversationIDWithCursor(ctx context.Context, conversationID, userID string, cursor *time.Time, limit int) ([]domain.Message, error) {
 	var messages []domain.Message
 
 	query := repo.db.WithContext(ctx).
 	Preload("Sender").
+	Preload("Attachments", orderAttachments).
 	Where("conversation_id = ?", conversationID).
+	Where("id NOT IN (SELECT message_id FROM hidden_messages WHERE user_id = ?)", userID).
 	Order("created_at DESC").
 	Limit(limit)
 
@@ -88,13 +98,43 @@ func (repo *messageRepositoryImpl) Delete(ctx context.Context, id string) error
 	return repo.db.WithContext(ctx).Delete(&domain.Message{}, "id = ?", id).Error
 }
 
+// HideForUser implements MessageRepository
+func (repo *messageRepositoryImpl) HideForUser(ctx context.Context, messageID, userID string) error {
+	hidden := &domain.HiddenMessage{
+		MessageID: messageID,
+		UserID: userID,
+	}
+
+	// Hiding the same message twice is a no-op
+	return repo.db.WithContext(ctx).
+	Clauses(clause.OnConflict{DoNothing: true}).
+	Create(hidden).Error
+}
+
+// IsHiddenForUser implements MessageRepository
+func (repo *messageRepositoryImpl) IsHiddenForUser(ctx context.Context, messageID, userID string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.HiddenMessage{}).
+	Where("message_id = ? AND user_id = ?", messageID, userID).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 // CountByConversationID implements MessageRepository
-func (repo *messageRepositoryImpl) CountByConversationID(ctx context.Context, conversationID string) (int64, error) {
+func (repo *messageRepositoryImpl) CountByConversationID(ctx context.Context, conversationID, userID string) (int64, error) {
 	var count int64
 	
 	err := repo.db.WithContext(ctx).
 	Model(&domain.Message{}).
 	Where("conversation_id = ?", conversationID).
+	Where("id NOT IN (SELECT message_id FROM hidden_messages WHERE user_id = ?)", userID).
 	Count(&count).Error
 
 	if err != nil {
@@ -105,12 +145,14 @@ func (repo *messageRepositoryImpl) CountByConversationID(ctx context.Context, co
 }
 
 // FindLastByConversationID implements MessageRepository
-func (repo *messageRepositoryImpl) FindLastByConversationID(ctx context.Context, conversationID string) (*domain.Message, error) {
+func (repo *messageRepositoryImpl) FindLastByConversationID(ctx context.Context, conversationID, userID string) (*domain.Message, error) {
 	var message domain.Message
 
 	err := repo.db.WithContext(ctx).
 	Preload("Sender").
+	Preload("Attachments", orderAttachments).
 	Where("conversation_id = ?", conversationID).
+	Where("id NOT IN (SELECT message_id FROM hidden_messages WHERE user_id = ?)", userID).
 	Order("created_at DESC").
 	First(&message).Error
 
@@ -0,0 +1,25 @@
+package message_mention
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// MessageMentionRepository interface for message mention operations
+type MessageMentionRepository interface {
+	// CreateBatch saves the users mentioned in a message (a user already mentioned is skipped)
+	CreateBatch(ctx context.Context, mentions []*domain.MessageMention) error
+
+	// FindUserIDsByMessageID returns the users mentioned in a message
+	FindUserIDsByMessageID(ctx context.Context, messageID string) ([]string, error)
+
+	// DeleteByMessageID removes all mentions of a message (e.g. deleted for everyone)
+	DeleteByMessageID(ctx context.Context, messageID string) error
+
+	// DeleteByMessageIDAndUserIDs removes the mentions of some users from a message (e.g. edited out)
+	DeleteByMessageIDAndUserIDs(ctx context.Context, messageID string, userIDs []string) error
+
+	// FindByUserID finds where a user was mentioned (newest first, with message and sender)
+	// Only conversations the user is still in, without messages they hid
+	FindByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.MessageMention, int64, error)
+}
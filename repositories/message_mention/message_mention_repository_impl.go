@@ -0,0 +1,87 @@
+package message_mention
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// messageMentionRepositoryImpl implements MessageMentionRepository
+type messageMentionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMessageMentionRepository creates a new message mention repository
+func NewMessageMentionRepository(db *gorm.DB) MessageMentionRepository {
+	return &messageMentionRepositoryImpl{db: db}
+}
+
+// CreateBatch implements MessageMentionRepository
+func (repo *messageMentionRepositoryImpl) CreateBatch(ctx context.Context, mentions []*domain.MessageMention) error {
+	return repo.db.WithContext(ctx).
+	Omit(clause.Associations).
+	Clauses(clause.OnConflict{DoNothing: true}).
+	Create(&mentions).Error
+}
+
+// FindUserIDsByMessageID implements MessageMentionRepository
+func (repo *messageMentionRepositoryImpl) FindUserIDsByMessageID(ctx context.Context, messageID string) ([]string, error) {
+	var userIDs []string
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.MessageMention{}).
+	Where("message_id = ?", messageID).
+	Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// DeleteByMessageID implements MessageMentionRepository
+func (repo *messageMentionRepositoryImpl) DeleteByMessageID(ctx context.Context, messageID string) error {
+	return repo.db.WithContext(ctx).
+	Where("message_id = ?", messageID).
+	Delete(&domain.MessageMention{}).Error
+}
+
+// DeleteByMessageIDAndUserIDs implements MessageMentionRepository
+func (repo *messageMentionRepositoryImpl) DeleteByMessageIDAndUserIDs(ctx context.Context, messageID string, userIDs []string) error {
+	return repo.db.WithContext(ctx).
+	Where("message_id = ? AND user_id IN ?", messageID, userIDs).
+	Delete(&domain.MessageMention{}).Error
+}
+
+// FindByUserID implements MessageMentionRepository
+func (repo *messageMentionRepositoryImpl) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.MessageMention, int64, error) {
+	query := repo.db.WithContext(ctx).
+	Model(&domain.MessageMention{}).
+	Where("user_id = ?", userID).
+	Where("conversation_id IN (SELECT conversation_id FROM participants WHERE user_id = ?)", userID).
+	Where("message_id NOT IN (SELECT message_id FROM hidden_messages WHERE user_id = ?)", userID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var mentions []domain.MessageMention
+	err := query.
+	Preload("Message").
+	Preload("Message.Sender").
+	Preload("Message.Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+	Order("created_at DESC").
+	Limit(limit).
+	Offset(offset).
+	Find(&mentions).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mentions, total, nil
+}
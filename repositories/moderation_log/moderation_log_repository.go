@@ -0,0 +1,18 @@
+package moderation_log
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// ModerationLogRepository interface for moderation log operations
+type ModerationLogRepository interface {
+	// Create records a new moderation action
+	Create(ctx context.Context, moderationLog *domain.ModerationLog) error
+
+	// FindByConversationID returns the moderation log of a conversation (newest first)
+	FindByConversationID(ctx context.Context, conversationID string, limit, offset int) ([]domain.ModerationLog, error)
+
+	// CountByConversationID counts moderation log entries in a conversation
+	CountByConversationID(ctx context.Context, conversationID string) (int64, error)
+}
@@ -0,0 +1,59 @@
+package moderation_log
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// moderationLogRepositoryImpl implements ModerationLogRepository
+type moderationLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewModerationLogRepository creates a new moderation log repository
+func NewModerationLogRepository(db *gorm.DB) ModerationLogRepository {
+	return &moderationLogRepositoryImpl{db: db}
+}
+
+// Create implements ModerationLogRepository
+func (repo *moderationLogRepositoryImpl) Create(ctx context.Context, moderationLog *domain.ModerationLog) error {
+	return repo.db.WithContext(ctx).Create(moderationLog).Error
+}
+
+// FindByConversationID implements ModerationLogRepository
+func (repo *moderationLogRepositoryImpl) FindByConversationID(ctx context.Context, conversationID string, limit, offset int) ([]domain.ModerationLog, error) {
+	var logs []domain.ModerationLog
+
+	err := repo.db.WithContext(ctx).
+	Preload("Actor").
+	Preload("TargetUser").
+	Where("conversation_id = ?", conversationID).
+	Order("created_at DESC").
+	Limit(limit).
+	Offset(offset).
+	Find(&logs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// CountByConversationID implements ModerationLogRepository
+func (repo *moderationLogRepositoryImpl) CountByConversationID(ctx context.Context, conversationID string) (int64, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.ModerationLog{}).
+	Where("conversation_id = ?", conversationID).
+	Count(&count).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
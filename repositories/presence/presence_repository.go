@@ -0,0 +1,31 @@
+package presence
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+)
+
+// PresenceRepository interface for live presence (stored in Redis with a TTL)
+type PresenceRepository interface {
+	// SetStatus marks a user as connected with a status (online, idle, dnd)
+	// The presence expires after ttl unless it is refreshed by Heartbeat
+	SetStatus(ctx context.Context, userID, status string, ttl time.Duration) error
+
+	// Heartbeat extends the presence of a connected user
+	// Returns false if the presence had already expired
+	Heartbeat(ctx context.Context, userID string, ttl time.Duration) (bool, error)
+
+	// Remove marks a user as offline
+	Remove(ctx context.Context, userID string) error
+
+	// GetStatuses returns the live status of users (users without presence are offline)
+	GetStatuses(ctx context.Context, userIDs []string) (map[string]string, error)
+
+	// Load fills IsOnline and PresenceStatus of users from their live status
+	Load(ctx context.Context, users ...*domain.User) error
+
+	// PopExpired returns users still tracked as online whose presence has expired (e.g. after a crash)
+	// and stops tracking them, so each expired user is returned only once
+	PopExpired(ctx context.Context) ([]string, error)
+}
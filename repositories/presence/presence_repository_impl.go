@@ -0,0 +1,160 @@
+package presence
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys
+const (
+	// presence:user:{userID} = status, expires without heartbeat
+	presenceKeyPrefix = "presence:user:"
+
+	// Set of users tracked as online (to find expired presence)
+	onlineSetKey = "presence:online"
+)
+
+// presenceRepositoryImpl implements PresenceRepository
+type presenceRepositoryImpl struct {
+	redis *redis.Client
+}
+
+// NewPresenceRepository creates a new presence repository
+func NewPresenceRepository(redis *redis.Client) PresenceRepository {
+	return &presenceRepositoryImpl{redis: redis}
+}
+
+// SetStatus implements PresenceRepository
+func (repo *presenceRepositoryImpl) SetStatus(ctx context.Context, userID, status string, ttl time.Duration) error {
+	pipe := repo.redis.TxPipeline()
+	pipe.Set(ctx, presenceKey(userID), status, ttl)
+	pipe.SAdd(ctx, onlineSetKey, userID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Heartbeat implements PresenceRepository
+func (repo *presenceRepositoryImpl) Heartbeat(ctx context.Context, userID string, ttl time.Duration) (bool, error) {
+	return repo.redis.Expire(ctx, presenceKey(userID), ttl).Result()
+}
+
+// Remove implements PresenceRepository
+func (repo *presenceRepositoryImpl) Remove(ctx context.Context, userID string) error {
+	pipe := repo.redis.TxPipeline()
+	pipe.Del(ctx, presenceKey(userID))
+	pipe.SRem(ctx, onlineSetKey, userID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetStatuses implements PresenceRepository
+func (repo *presenceRepositoryImpl) GetStatuses(ctx context.Context, userIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return statuses, nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for idx, userID := range userIDs {
+		keys[idx] = presenceKey(userID)
+	}
+
+	values, err := repo.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, value := range values {
+		status, ok := value.(string)
+		if !ok {
+			status = domain.PresenceOffline
+		}
+		statuses[userIDs[idx]] = status
+	}
+
+	return statuses, nil
+}
+
+// Load implements PresenceRepository
+func (repo *presenceRepositoryImpl) Load(ctx context.Context, users ...*domain.User) error {
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		if user != nil && user.ID != "" {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	statuses, err := repo.GetStatuses(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user == nil || user.ID == "" {
+			continue
+		}
+		user.PresenceStatus = statuses[user.ID]
+		user.IsOnline = user.PresenceStatus != domain.PresenceOffline
+	}
+
+	return nil
+}
+
+// PopExpired implements PresenceRepository
+func (repo *presenceRepositoryImpl) PopExpired(ctx context.Context) ([]string, error) {
+	// 1. Get users tracked as online
+	userIDs, err := repo.redis.SMembers(ctx, onlineSetKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Check which of them still have a presence key
+	pipe := repo.redis.Pipeline()
+	exists := make([]*redis.IntCmd, len(userIDs))
+	for idx, userID := range userIDs {
+		exists[idx] = pipe.Exists(ctx, presenceKey(userID))
+	}
+	if len(userIDs) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Stop tracking expired users (SREM result makes sure only one sweeper claims each user)
+	var expired []string
+	for idx, userID := range userIDs {
+		if exists[idx].Val() > 0 {
+			continue
+		}
+
+		removed, err := repo.redis.SRem(ctx, onlineSetKey, userID).Result()
+		if err != nil {
+			return nil, err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		// The user may have reconnected in between: keep tracking them
+		stillOnline, err := repo.redis.Exists(ctx, presenceKey(userID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if stillOnline > 0 {
+			repo.redis.SAdd(ctx, onlineSetKey, userID)
+			continue
+		}
+
+		expired = append(expired, userID)
+	}
+
+	return expired, nil
+}
+
+// presenceKey builds the Redis key of a user's presence
+func presenceKey(userID string) string {
+	return presenceKeyPrefix + userID
+}
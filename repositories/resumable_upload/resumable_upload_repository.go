@@ -0,0 +1,45 @@
+package resumable_upload
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUploadLocked is returned by Lock when another request holds the upload's lease (e.g. a chunk being received),
+// and by the writes of a request whose lease was taken over after it expired
+var ErrUploadLocked = errors.New("resumable upload is locked")
+
+// ResumableUploadRepository interface for resumable (tus) upload operations
+type ResumableUploadRepository interface {
+	// Create saves a new upload
+	Create(ctx context.Context, upload *domain.ResumableUpload) error
+
+	// FindByID finds an upload by ID
+	FindByID(ctx context.Context, id string) (*domain.ResumableUpload, error)
+
+	// Lock takes the upload's lease until the given time for the request identified by token, so a single request
+	// at a time writes the upload across all API instances. Returns ErrUploadLocked or gorm.ErrRecordNotFound
+	Lock(ctx context.Context, id, token string, until time.Time) error
+
+	// ExtendLock pushes back the end of a lease the token holds. Returns ErrUploadLocked if it was taken over
+	ExtendLock(ctx context.Context, id, token string, until time.Time) error
+
+	// Unlock releases a lease the token holds
+	Unlock(ctx context.Context, id, token string) error
+
+	// UpdateOffset saves the number of bytes received and pushes back the expiry.
+	// Only by the lease holder, returns ErrUploadLocked otherwise
+	UpdateOffset(ctx context.Context, id, token string, offset int64, expiresAt time.Time) error
+
+	// MarkCompleted links a complete upload to the attachment created from it.
+	// Only by the lease holder, returns ErrUploadLocked otherwise
+	MarkCompleted(ctx context.Context, id, token, attachmentID string) error
+
+	// Delete removes an upload. Only by the lease holder, returns ErrUploadLocked otherwise
+	Delete(ctx context.Context, id, token string) error
+
+	// FindExpired finds uploads that expired before the given time (oldest first)
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]domain.ResumableUpload, error)
+}
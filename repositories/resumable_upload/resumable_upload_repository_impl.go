@@ -0,0 +1,142 @@
+package resumable_upload
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// resumableUploadRepositoryImpl implements ResumableUploadRepository
+type resumableUploadRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewResumableUploadRepository creates a new resumable upload repository
+func NewResumableUploadRepository(db *gorm.DB) ResumableUploadRepository {
+	return &resumableUploadRepositoryImpl{db: db}
+}
+
+// Create implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) Create(ctx context.Context, upload *domain.ResumableUpload) error {
+	return repo.db.WithContext(ctx).Create(upload).Error
+}
+
+// FindByID implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.ResumableUpload, error) {
+	var upload domain.ResumableUpload
+
+	err := repo.db.WithContext(ctx).
+	Where("id = ?", id).
+	First(&upload).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// Lock implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) Lock(ctx context.Context, id, token string, until time.Time) error {
+	// Take the lease in one statement, only if it is free
+	result := repo.db.WithContext(ctx).
+	Model(&domain.ResumableUpload{}).
+	Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, time.Now()).
+	Updates(map[string]interface{}{"lock_token": token, "locked_until": until})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// Not taken: held by another request, or no such upload
+	var count int64
+	err := repo.db.WithContext(ctx).
+	Model(&domain.ResumableUpload{}).
+	Where("id = ?", id).
+	Count(&count).Error
+
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrUploadLocked
+}
+
+// ExtendLock implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) ExtendLock(ctx context.Context, id, token string, until time.Time) error {
+	return repo.updateLocked(ctx, id, token, map[string]interface{}{"locked_until": until})
+}
+
+// Unlock implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) Unlock(ctx context.Context, id, token string) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.ResumableUpload{}).
+	Where("id = ? AND lock_token = ?", id, token).
+	Updates(map[string]interface{}{"lock_token": nil, "locked_until": nil}).Error
+}
+
+// UpdateOffset implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) UpdateOffset(ctx context.Context, id, token string, offset int64, expiresAt time.Time) error {
+	return repo.updateLocked(ctx, id, token, map[string]interface{}{"upload_offset": offset, "expires_at": expiresAt})
+}
+
+// MarkCompleted implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) MarkCompleted(ctx context.Context, id, token, attachmentID string) error {
+	return repo.updateLocked(ctx, id, token, map[string]interface{}{"attachment_id": attachmentID})
+}
+
+// Delete implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) Delete(ctx context.Context, id, token string) error {
+	result := repo.db.WithContext(ctx).
+	Where("id = ? AND lock_token = ?", id, token).
+	Delete(&domain.ResumableUpload{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUploadLocked
+	}
+	return nil
+}
+
+// FindExpired implements ResumableUploadRepository
+func (repo *resumableUploadRepositoryImpl) FindExpired(ctx context.Context, before time.Time, limit int) ([]domain.ResumableUpload, error) {
+	var uploads []domain.ResumableUpload
+
+	err := repo.db.WithContext(ctx).
+	Where("expires_at < ?", before).
+	Order("expires_at ASC").
+	Limit(limit).
+	Find(&uploads).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// updateLocked updates an upload whose lease the token holds.
+// Returns ErrUploadLocked when it doesn't (the lease was taken over, or the upload is gone)
+func (repo *resumableUploadRepositoryImpl) updateLocked(ctx context.Context, id, token string, values map[string]interface{}) error {
+	result := repo.db.WithContext(ctx).
+	Model(&domain.ResumableUpload{}).
+	Where("id = ? AND lock_token = ?", id, token).
+	Updates(values)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUploadLocked
+	}
+	return nil
+}
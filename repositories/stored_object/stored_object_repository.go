@@ -0,0 +1,31 @@
+package stored_object
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+)
+
+// StoredObjectRepository interface for the reference counts of files in the object store
+type StoredObjectRepository interface {
+	// Acquire adds a reference to an object, creating it with one reference if it isn't tracked yet.
+	// Returns the new reference count and fills object.Ready and object.Thumbnails: the file must be stored
+	// unless the object is ready (an object left with no reference isn't, its deletion may have started)
+	Acquire(ctx context.Context, object *domain.StoredObject) (int, error)
+
+	// MarkReady records that the object's file and the given thumbnails were written to the object store
+	MarkReady(ctx context.Context, objectKey string, thumbnails domain.AttachmentThumbnails) error
+
+	// Release removes a reference to an object. When it was the last one, deleteFile removes the file
+	// while the object is locked (so no upload can reuse it meanwhile), then the object is deleted.
+	// If deleteFile fails the object is kept with no reference, for a later retry
+	Release(ctx context.Context, objectKey string, deleteFile func() error) error
+
+	// FindUnreferenced finds objects left with no reference (failed deletions) since before the given time.
+	// Ordered by key, starting after afterKey (empty = from the start)
+	FindUnreferenced(ctx context.Context, updatedBefore time.Time, afterKey string, limit int) ([]domain.StoredObject, error)
+
+	// DeleteUnreferenced deletes an object if it still has no reference, deleteFile removing the file first.
+	// Returns whether it was deleted
+	DeleteUnreferenced(ctx context.Context, objectKey string, deleteFile func() error) (bool, error)
+}
@@ -0,0 +1,143 @@
+package stored_object
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// storedObjectRepositoryImpl implements StoredObjectRepository
+type storedObjectRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStoredObjectRepository creates a new stored object repository
+func NewStoredObjectRepository(db *gorm.DB) StoredObjectRepository {
+	return &storedObjectRepositoryImpl{db: db}
+}
+
+// Acquire implements StoredObjectRepository
+func (repo *storedObjectRepositoryImpl) Acquire(ctx context.Context, object *domain.StoredObject) (int, error) {
+	object.RefCount = 1
+	object.Ready = false
+
+	// Insert or increment in one statement, so concurrent uploads of the same file count correctly
+	err := repo.db.WithContext(ctx).
+	Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "object_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"ref_count": gorm.Expr("stored_objects.ref_count + 1"),
+			"ready": gorm.Expr("stored_objects.ready AND stored_objects.ref_count > 0"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}, clause.Returning{Columns: []clause.Column{{Name: "ref_count"}, {Name: "ready"}, {Name: "thumbnails"}}}).
+	Create(object).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return object.RefCount, nil
+}
+
+// MarkReady implements StoredObjectRepository
+func (repo *storedObjectRepositoryImpl) MarkReady(ctx context.Context, objectKey string, thumbnails domain.AttachmentThumbnails) error {
+	return repo.db.WithContext(ctx).
+	Model(&domain.StoredObject{}).
+	Where("object_key = ?", objectKey).
+	Updates(map[string]interface{}{
+		"ready": true,
+		"thumbnails": thumbnails,
+		"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+	}).Error
+}
+
+// Release implements StoredObjectRepository
+func (repo *storedObjectRepositoryImpl) Release(ctx context.Context, objectKey string, deleteFile func() error) error {
+	var deleteErr error
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lock the object (an upload of the same file waits until we are done)
+		var object domain.StoredObject
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("object_key = ?", objectKey).
+			First(&object).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 2. Other attachments still use it
+		if object.RefCount > 1 {
+			return tx.Model(&object).Update("ref_count", object.RefCount-1).Error
+		}
+
+		// 3. Last reference: delete the file, then the object (kept with no reference if the file remains)
+		if deleteErr = deleteFile(); deleteErr != nil {
+			return tx.Model(&object).Update("ref_count", 0).Error
+		}
+		return tx.Delete(&object).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return deleteErr
+}
+
+// FindUnreferenced implements StoredObjectRepository
+func (repo *storedObjectRepositoryImpl) FindUnreferenced(ctx context.Context, updatedBefore time.Time, afterKey string, limit int) ([]domain.StoredObject, error) {
+	var objects []domain.StoredObject
+
+	err := repo.db.WithContext(ctx).
+	Where("ref_count = 0 AND updated_at < ?", updatedBefore).
+	Where("object_key > ?", afterKey).
+	Order("object_key ASC").
+	Limit(limit).
+	Find(&objects).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// DeleteUnreferenced implements StoredObjectRepository
+func (repo *storedObjectRepositoryImpl) DeleteUnreferenced(ctx context.Context, objectKey string, deleteFile func() error) (bool, error) {
+	deleted := false
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lock the object, skip it if an upload of the same file references it again
+		var object domain.StoredObject
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("object_key = ? AND ref_count = 0", objectKey).
+			First(&object).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 2. Delete the file, then the object
+		if err := deleteFile(); err != nil {
+			return err
+		}
+		deleted = true
+		return tx.Delete(&object).Error
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,32 @@
+package upload_reservation
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by Reserve when the upload doesn't fit in the user's storage quota
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// ErrRateExceeded is returned by Reserve when the user reached their number of uploads in the window
+var ErrRateExceeded = errors.New("upload rate exceeded")
+
+// UploadLimits are the per-user limits checked by Reserve, 0 means unlimited
+type UploadLimits struct {
+	QuotaBytes int64
+	UploadsPerWindow int64
+	Window time.Duration
+}
+
+// UploadReservationRepository interface for reserving uploads against the per-user limits
+type UploadReservationRepository interface {
+	// Reserve records the reservation if the user's attachments and other reservations leave room for it, the check
+	// and the insert hold a lock on the user so concurrent uploads can't exceed the limits together.
+	// Reservations older than staleAfter (e.g. left by a crash) are removed. Returns ErrQuotaExceeded or ErrRateExceeded
+	Reserve(ctx context.Context, reservation *domain.UploadReservation, limits UploadLimits, staleAfter time.Duration) error
+
+	// Release deletes a reservation, once its attachment is recorded or its upload failed
+	Release(ctx context.Context, id string) error
+}
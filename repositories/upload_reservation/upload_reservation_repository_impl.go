@@ -0,0 +1,110 @@
+package upload_reservation
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uploadReservationRepositoryImpl implements UploadReservationRepository
+type uploadReservationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUploadReservationRepository creates a new upload reservation repository
+func NewUploadReservationRepository(db *gorm.DB) UploadReservationRepository {
+	return &uploadReservationRepositoryImpl{db: db}
+}
+
+// Reserve implements UploadReservationRepository
+func (repo *uploadReservationRepositoryImpl) Reserve(ctx context.Context, reservation *domain.UploadReservation, limits UploadLimits, staleAfter time.Duration) error {
+	now := time.Now()
+
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lock the user's row: the reservations of a user are checked and made one at a time.
+		// NO KEY UPDATE doesn't block rows referencing the user (messages, attachments...)
+		var locked []string
+		err := tx.Model(&domain.User{}).
+		Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Where("id = ?", reservation.UserID).
+		Pluck("id", &locked).Error
+
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. Drop reservations whose upload never finished
+		err = tx.Where("user_id = ? AND created_at < ?", reservation.UserID, now.Add(-staleAfter)).
+		Delete(&domain.UploadReservation{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		// 3. Storage quota: attachments plus uploads in progress
+		if limits.QuotaBytes > 0 {
+			var stored, reserved int64
+			err := tx.Model(&domain.Attachment{}).
+			Select("COALESCE(SUM(size), 0)").
+			Where("uploader_id = ?", reservation.UserID).
+			Scan(&stored).Error
+
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&domain.UploadReservation{}).
+			Select("COALESCE(SUM(size), 0)").
+			Where("user_id = ?", reservation.UserID).
+			Scan(&reserved).Error
+
+			if err != nil {
+				return err
+			}
+			if stored+reserved+reservation.Size > limits.QuotaBytes {
+				return ErrQuotaExceeded
+			}
+		}
+
+		// 4. Upload rate: attachments and uploads in progress in the window
+		if limits.UploadsPerWindow > 0 {
+			since := now.Add(-limits.Window)
+
+			var uploaded, inProgress int64
+			err := tx.Model(&domain.Attachment{}).
+			Where("uploader_id = ? AND created_at > ?", reservation.UserID, since).
+			Count(&uploaded).Error
+
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&domain.UploadReservation{}).
+			Where("user_id = ? AND created_at > ?", reservation.UserID, since).
+			Count(&inProgress).Error
+
+			if err != nil {
+				return err
+			}
+			if uploaded+inProgress >= limits.UploadsPerWindow {
+				return ErrRateExceeded
+			}
+		}
+
+		// 5. Record the reservation, released with the lock
+		return tx.Create(reservation).Error
+	})
+}
+
+// Release implements UploadReservationRepository
+func (repo *uploadReservationRepositoryImpl) Release(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).
+	Where("id = ?", id).
+	Delete(&domain.UploadReservation{}).Error
+}
@@ -3,9 +3,13 @@ package user
 import (
 	"chatapp-api/models/domain"
 	"context"
+	"errors"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // userRepositoryImpl is an implementation of UserRepository interface
@@ -33,6 +37,16 @@ func (userRepo *userRepositoryImpl) FindByID(ctx context.Context, id string) (*d
 	return &user, nil
 }
 
+// FindByUsername implements UserRepository
+func (userRepo *userRepositoryImpl) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var user domain.User
+	err := userRepo.db.WithContext(ctx).Where("LOWER(username) = LOWER(?)", username).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // FindByEmail implements UserRepository
 func (userRepo *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
@@ -43,9 +57,61 @@ func (userRepo *userRepositoryImpl) FindByEmail(ctx context.Context, email strin
 	return &user, nil
 }
 
+// Search implements UserRepository
+func (userRepo *userRepositoryImpl) Search(ctx context.Context, query string, excludeIDs []string, limit, offset int) ([]domain.User, int64, error) {
+	query = strings.ToLower(query)
+	prefix := escapeLike(query) + "%"
+
+	// Prefix match (LIKE) or trigram similarity (%), both served by the trigram indexes
+	search := userRepo.db.WithContext(ctx).
+	Model(&domain.User{}).
+	Where("(LOWER(name) LIKE ? OR LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR LOWER(name) % ? OR LOWER(username) % ? OR LOWER(email) % ?)",
+		prefix, prefix, prefix, query, query, query)
+
+	if len(excludeIDs) > 0 {
+		search = search.Where("id NOT IN ?", excludeIDs)
+	}
+
+	var total int64
+	if err := search.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Prefix matches first, then the most similar
+	var users []domain.User
+	err := search.
+	Order(clause.OrderBy{Expression: clause.Expr{
+		SQL: "CASE WHEN LOWER(name) LIKE ? OR LOWER(username) LIKE ? OR LOWER(email) LIKE ? THEN 0 ELSE 1 END, " +
+			"GREATEST(similarity(LOWER(name), ?), similarity(COALESCE(LOWER(username), ''), ?), similarity(LOWER(email), ?)) DESC, name",
+		Vars: []interface{}{prefix, prefix, prefix, query, query, query},
+		WithoutParentheses: true,
+	}}).
+	Limit(limit).
+	Offset(offset).
+	Find(&users).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// escapeLike escapes LIKE wildcards so the query is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 // Update implements UserRepository
 func (userRepo *userRepositoryImpl) Update(ctx context.Context, user *domain.User) error {
-	return userRepo.db.WithContext(ctx).Save(user).Error
+	err := userRepo.db.WithContext(ctx).Save(user).Error
+
+	// Two users may pass the availability check at the same time: the unique index decides
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_users_username_lower" {
+		return ErrUsernameTaken
+	}
+	return err
 }
 
 // Delete implements UserRepository
@@ -53,15 +119,23 @@ func (userRepo *userRepositoryImpl) Delete(ctx context.Context, id string) error
 	return userRepo.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id).Error
 }
 
-// UpdateOnlineStatus implements UserRepository
-func (userRepo *userRepositoryImpl) UpdateOnlineStatus(ctx context.Context, id string, isOnline bool) error {
-	updates := map[string]interface{}{
-		"is_online": isOnline,
-	}
+// UpdateLastSeen implements UserRepository
+func (userRepo *userRepositoryImpl) UpdateLastSeen(ctx context.Context, id string, lastSeen time.Time) error {
+	return userRepo.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("last_seen", lastSeen).Error
+}
 
-	if !isOnline {
-		updates["last_seen"] = time.Now()
-	}
+// UpdatePresenceSettings implements UserRepository
+func (userRepo *userRepositoryImpl) UpdatePresenceSettings(ctx context.Context, user *domain.User) error {
+	return userRepo.db.WithContext(ctx).Model(user).
+		Select("do_not_disturb", "status_text", "status_emoji", "status_expires_at").
+		Updates(user).Error
+}
 
-	return userRepo.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(updates).Error
+// ExistsByAvatar implements UserRepository
+func (userRepo *userRepositoryImpl) ExistsByAvatar(ctx context.Context, attachmentID string) (bool, error) {
+	var count int64
+	err := userRepo.db.WithContext(ctx).Model(&domain.User{}).
+		Where("avatar_attachment_id = ?", attachmentID).
+		Count(&count).Error
+	return count > 0, err
 }
\ No newline at end of file
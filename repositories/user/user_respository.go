@@ -3,8 +3,13 @@ package user
 import (
 	"chatapp-api/models/domain"
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrUsernameTaken is returned by Update when another user got the username first (unique index)
+var ErrUsernameTaken = errors.New("username is already taken")
+
 // UserRepository interface for User Repository
 type UserRepository interface {
 	// Create creates a new user
@@ -13,15 +18,29 @@ type UserRepository interface {
 	// FindByID finds a user by ID
 	FindByID(ctx context.Context, id string) (*domain.User, error)
 
+	// FindByUsername finds a user by username (case-insensitive)
+	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+
 	// FindByEmail finds a user by email (for duplicate check)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 
+	// Search finds users by name, username or email (prefix or trigram similarity), best matches first
+	// Users in excludeIDs are left out. Returns the page of users and the total number of matches
+	Search(ctx context.Context, query string, excludeIDs []string, limit, offset int) ([]domain.User, int64, error)
+
 	// Update updates a user's data
+	// Returns ErrUsernameTaken if the username is already used by another user
 	Update(ctx context.Context, user *domain.User) error
 
 	// Delete deletes a user
 	Delete(ctx context.Context, id string) error
 
-	// UpdateOnlineStatus updates a user's online status
-	UpdateOnlineStatus(ctx context.Context, id string, isOnline bool) error
+	// UpdateLastSeen saves when a user was last online
+	UpdateLastSeen(ctx context.Context, id string, lastSeen time.Time) error
+
+	// UpdatePresenceSettings saves a user's DND flag and custom status
+	UpdatePresenceSettings(ctx context.Context, user *domain.User) error
+
+	// ExistsByAvatar reports whether the attachment is a user's profile picture
+	ExistsByAvatar(ctx context.Context, attachmentID string) (bool, error)
 }
\ No newline at end of file
@@ -0,0 +1,28 @@
+package user_block
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+)
+
+// UserBlockRepository interface for user block operations
+type UserBlockRepository interface {
+	// Create blocks a user (blocking twice is a no-op)
+	Create(ctx context.Context, block *domain.UserBlock) error
+
+	// Delete unblocks a user
+	// Returns false if the user wasn't blocked
+	Delete(ctx context.Context, blockerID, blockedID string) (bool, error)
+
+	// HasBlocked checks whether blockerID blocked blockedID
+	HasBlocked(ctx context.Context, blockerID, blockedID string) (bool, error)
+
+	// IsBlockedEitherWay checks whether one of the two users blocked the other
+	IsBlockedEitherWay(ctx context.Context, userID1, userID2 string) (bool, error)
+
+	// FindByBlockerID finds all users blocked by a user (newest first, with blocked user info)
+	FindByBlockerID(ctx context.Context, blockerID string) ([]domain.UserBlock, error)
+
+	// FindRelatedUserIDs returns users that the user blocked or was blocked by
+	FindRelatedUserIDs(ctx context.Context, userID string) ([]string, error)
+}
@@ -0,0 +1,113 @@
+package user_block
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userBlockRepositoryImpl implements UserBlockRepository
+type userBlockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUserBlockRepository creates a new user block repository
+func NewUserBlockRepository(db *gorm.DB) UserBlockRepository {
+	return &userBlockRepositoryImpl{db: db}
+}
+
+// Create implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) Create(ctx context.Context, block *domain.UserBlock) error {
+	return repo.db.WithContext(ctx).
+	Omit(clause.Associations).
+	Clauses(clause.OnConflict{DoNothing: true}).
+	Create(block).Error
+}
+
+// Delete implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) Delete(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	result := repo.db.WithContext(ctx).
+	Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+	Delete(&domain.UserBlock{})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// HasBlocked implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) HasBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.UserBlock{}).
+	Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// IsBlockedEitherWay implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) IsBlockedEitherWay(ctx context.Context, userID1, userID2 string) (bool, error) {
+	var count int64
+
+	err := repo.db.WithContext(ctx).
+	Model(&domain.UserBlock{}).
+	Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID1, userID2, userID2, userID1).
+	Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindByBlockerID implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) FindByBlockerID(ctx context.Context, blockerID string) ([]domain.UserBlock, error) {
+	var blocks []domain.UserBlock
+
+	err := repo.db.WithContext(ctx).
+	Preload("Blocked").
+	Where("blocker_id = ?", blockerID).
+	Order("created_at DESC").
+	Find(&blocks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// FindRelatedUserIDs implements UserBlockRepository
+func (repo *userBlockRepositoryImpl) FindRelatedUserIDs(ctx context.Context, userID string) ([]string, error) {
+	var blocks []domain.UserBlock
+
+	err := repo.db.WithContext(ctx).
+	Where("blocker_id = ? OR blocked_id = ?", userID, userID).
+	Find(&blocks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if block.BlockerID == userID {
+			userIDs = append(userIDs, block.BlockedID)
+		} else {
+			userIDs = append(userIDs, block.BlockerID)
+		}
+	}
+
+	return userIDs, nil
+}
@@ -1,11 +1,15 @@
 package routes
 
 import (
+	"chatapp-api/apps/storage"
 	"chatapp-api/config"
 	"chatapp-api/controllers/auth"
+	"chatapp-api/controllers/contact"
 	"chatapp-api/controllers/conversation"
+	"chatapp-api/controllers/invite"
 	"chatapp-api/controllers/message"
 	"chatapp-api/controllers/upload"
+	"chatapp-api/controllers/user"
 	"chatapp-api/exceptions"
 	"chatapp-api/middleware"
 	"chatapp-api/websocket"
@@ -20,6 +24,9 @@ func SetupRouter(
 	convController conversation.ConversationController,
 	messageController message.MessageController,
 	uploadController upload.UploadController,
+	inviteController invite.InviteController,
+	userController user.UserController,
+	contactController contact.ContactController,
 	hub *websocket.Hub) *gin.Engine {
 	// Create router
 	router := gin.Default()
@@ -27,6 +34,11 @@ func SetupRouter(
 	// Global middleware
 	router.Use(exceptions.ErrorHandler())
 
+	// Local storage: serve uploaded files from disk through signed URLs (no external storage needed in development)
+	if config.Storage.Driver == "local" {
+		router.GET("/files/*key", storage.ServeLocalFiles(config))
+	}
+
 	// API v1
 	    v1 := router.Group("/api/v1")
     {
@@ -41,6 +53,7 @@ func SetupRouter(
             authRoutes.Use(middleware.AuthMiddleware(config))
             authRoutes.GET("/me", authController.GetMe)
 			authRoutes.PUT("/me", authController.UpdateProfile)
+			authRoutes.GET("/me/storage", uploadController.GetStorageUsage)
         }
 
         // Conversation routes
@@ -54,6 +67,20 @@ func SetupRouter(
 			conversationRoutes.POST("/:id/participants", convController.AddParticipants)
 			conversationRoutes.DELETE("/:id/leave", convController.LeaveConversation)
 			conversationRoutes.DELETE("/:id/participants/:userId", convController.KickParticipant)
+			conversationRoutes.PUT("/:id/participants/:userId/role", convController.UpdateParticipantRole)
+			conversationRoutes.POST("/:id/transfer-ownership", convController.TransferOwnership)
+			conversationRoutes.GET("/:id/moderation-log", convController.GetModerationLog)
+			conversationRoutes.POST("/:id/bans", convController.BanParticipant)
+			conversationRoutes.GET("/:id/bans", convController.GetBans)
+			conversationRoutes.DELETE("/:id/bans/:userId", convController.UnbanParticipant)
+
+			// Invite links & join requests (owner/admin)
+			conversationRoutes.POST("/:id/invites", inviteController.CreateInvite)
+			conversationRoutes.GET("/:id/invites", inviteController.GetInvites)
+			conversationRoutes.DELETE("/:id/invites/:inviteId", inviteController.RevokeInvite)
+			conversationRoutes.GET("/:id/join-requests", inviteController.GetJoinRequests)
+			conversationRoutes.POST("/:id/join-requests/:requestId/approve", inviteController.ApproveJoinRequest)
+			conversationRoutes.POST("/:id/join-requests/:requestId/reject", inviteController.RejectJoinRequest)
         
 			// Message routes (nested under conversations)
 			//POST & GET messages wihtin a conversation
@@ -71,11 +98,80 @@ func SetupRouter(
 			messageRoutes.DELETE("/:messageId", messageController.DeleteMessage)
 		}
 
+		// Mention routes (where the current user was mentioned)
+		mentionRoutes := v1.Group("/mentions")
+		mentionRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			mentionRoutes.GET("", messageController.GetMentions)
+		}
+
+		// Invite routes (join a group through an invite code)
+		inviteRoutes := v1.Group("/invites")
+		inviteRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			inviteRoutes.POST("/:code/join", inviteController.JoinByCode)
+		}
+
+		// User routes (directory, blocking, presence)
+		userRoutes := v1.Group("/users")
+		userRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			userRoutes.GET("/search", userController.SearchUsers)
+			userRoutes.PUT("/me/presence", userController.UpdatePresence)
+			userRoutes.GET("/blocked", userController.GetBlockedUsers)
+			userRoutes.GET("/by-username/:username", userController.GetUserByUsername)
+			userRoutes.GET("/:id", userController.GetUserByID)
+			userRoutes.POST("/:id/block", userController.BlockUser)
+			userRoutes.DELETE("/:id/block", userController.UnblockUser)
+		}
+
+		// Message request routes (DMs from non-contacts for users who only accept DMs from contacts)
+		messageRequestRoutes := v1.Group("/message-requests")
+		messageRequestRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			messageRequestRoutes.GET("", convController.GetMessageRequests)
+			messageRequestRoutes.POST("/:id/accept", convController.AcceptMessageRequest)
+			messageRequestRoutes.POST("/:id/decline", convController.DeclineMessageRequest)
+		}
+
+		// Contact routes (contact list and contact requests)
+		contactRoutes := v1.Group("/contacts")
+		contactRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			contactRoutes.GET("", contactController.GetContacts)
+			contactRoutes.DELETE("/:userId", contactController.RemoveContact)
+			contactRoutes.POST("/requests", contactController.SendRequest)
+			contactRoutes.GET("/requests", contactController.GetRequests)
+			contactRoutes.POST("/requests/:id/accept", contactController.AcceptRequest)
+			contactRoutes.POST("/requests/:id/decline", contactController.DeclineRequest)
+		}
+
+		// tus discovery (OPTIONS): clients send it without credentials, before any resumable upload
+		v1.OPTIONS("/upload/resumable", middleware.TusMiddleware(config), uploadController.ResumableOptions)
+
 		// Upload routes
 		uploadRoutes := v1.Group("/upload")
 		uploadRoutes.Use(middleware.AuthMiddleware(config))
 		{
 			uploadRoutes.POST("", uploadController.UploadFile)
+
+			// Resumable uploads (tus 1.0 protocol) for large files on unreliable networks
+			resumableRoutes := uploadRoutes.Group("/resumable")
+			resumableRoutes.Use(middleware.TusMiddleware(config))
+			{
+				resumableRoutes.POST("", uploadController.CreateResumableUpload)
+				resumableRoutes.HEAD("/:id", uploadController.HeadResumableUpload)
+				resumableRoutes.PATCH("/:id", uploadController.PatchResumableUpload)
+				resumableRoutes.DELETE("/:id", uploadController.DeleteResumableUpload)
+				resumableRoutes.GET("/:id", uploadController.GetResumableUpload)
+			}
+		}
+
+		// Media routes (signed download URLs for uploaded files, participants only)
+		mediaRoutes := v1.Group("/media")
+		mediaRoutes.Use(middleware.AuthMiddleware(config))
+		{
+			mediaRoutes.GET("/:id", uploadController.GetMedia)
 		}
 
 		// WebSocket routes
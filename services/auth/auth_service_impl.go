@@ -8,7 +8,13 @@ import (
 	"chatapp-api/utils"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	attachmentRepo "chatapp-api/repositories/attachment"
+	presenceRepo "chatapp-api/repositories/presence"
 	userRepo "chatapp-api/repositories/user"
 
 	"golang.org/x/crypto/bcrypt"
@@ -18,13 +24,17 @@ import (
 // authServiceImpl implements AuthService interface
 type authServiceImpl struct {
 	userRepo userRepo.UserRepository
+	presenceRepo presenceRepo.PresenceRepository
+	attachmentRepo attachmentRepo.AttachmentRepository
 	config  *config.Config
 }
 
 // NewAuthService Create new Instance of AuthService
-func NewAuthService(userRepo userRepo.UserRepository, config *config.Config) AuthService {
+func NewAuthService(userRepo userRepo.UserRepository, presenceRepo presenceRepo.PresenceRepository, attachmentRepo attachmentRepo.AttachmentRepository, config *config.Config) AuthService {
 	return &authServiceImpl{
 		userRepo: userRepo,
+		presenceRepo: presenceRepo,
+		attachmentRepo: attachmentRepo,
 		config:  config,
 	}
 }
@@ -59,7 +69,7 @@ func (authService *authServiceImpl) Register(ctx context.Context, req *web.Regis
 	}
 
 	// 4. Generate tokens and return response
-	return authService.generateAuthResponse(user)
+	return authService.generateAuthResponse(ctx, user)
 }
 
 // Login to Authenticate User
@@ -79,7 +89,7 @@ func (authService *authServiceImpl) Login(ctx context.Context, req *web.LoginReq
 	}
 
 	// 3. Generate tokens and return response
-	return authService.generateAuthResponse(user)
+	return authService.generateAuthResponse(ctx, user)
 }
 
 // RefreshToken to Create New Access Token from Refresh Token
@@ -113,11 +123,20 @@ func (authService *authServiceImpl) GetUserByID(ctx context.Context, id string)
 		return nil, err
 	}
 
+	authService.loadPresence(ctx, user)
 	return user, nil
 }
 
+// loadPresence fills the user's live presence from Redis (best effort: offline on failure)
+func (authService *authServiceImpl) loadPresence(ctx context.Context, user *domain.User) {
+	if err := authService.presenceRepo.Load(ctx, user); err != nil {
+		log.Printf("Failed to load presence of user %s: %v", user.ID, err)
+		user.PresenceStatus = domain.PresenceOffline
+	}
+}
+
 // generateAuthResponse is a helper function to create response with tokens
-func (authService *authServiceImpl) generateAuthResponse(user *domain.User) (*web.AuthResponse, error) {
+func (authService *authServiceImpl) generateAuthResponse(ctx context.Context, user *domain.User) (*web.AuthResponse, error) {
 	// Generate access token
 	accessToken, expiresAt, err := utils.GenerateAccessToken(user.ID, authService.config.JWT.Secret)
 	if err != nil {
@@ -130,15 +149,24 @@ func (authService *authServiceImpl) generateAuthResponse(user *domain.User) (*we
 		return nil, err
 	}
 
-	// Return response
+	// Return response (with live presence)
+	authService.loadPresence(ctx, user)
+	user.ClearExpiredStatus(time.Now())
 	return &web.AuthResponse{
 		User: web.UserResponse{
 			ID: user.ID,
 			Name: user.Name,
+			Username: user.Username,
 			Email: user.Email,
 			AvatarURL: user.AvatarURL,
 			IsOnline: user.IsOnline,
 			LastSeen: user.LastSeen,
+			PresenceVisibility: user.PresenceVisibility,
+			DMContactsOnly: user.DMContactsOnly,
+			Status: user.PresenceStatus,
+			StatusText: user.StatusText,
+			StatusEmoji: user.StatusEmoji,
+			StatusExpiresAt: user.StatusExpiresAt,
 		},
 		AccessToken: accessToken,
 		RefreshToken: refreshToken,
@@ -158,14 +186,95 @@ func (service *authServiceImpl) UpdateProfile(ctx context.Context, userID string
     if req.Name != nil {
         user.Name = *req.Name
     }
-    if req.AvatarURL != nil {
-        user.AvatarURL = req.AvatarURL
+    if req.AvatarURL != nil || req.AvatarID != nil {
+        if err := service.changeAvatar(ctx, user, req.AvatarURL, req.AvatarID); err != nil {
+            return nil, err
+        }
+    }
+    if req.PresenceVisibility != nil {
+        user.PresenceVisibility = *req.PresenceVisibility
+    }
+    if req.DMContactsOnly != nil {
+        user.DMContactsOnly = *req.DMContactsOnly
+    }
+    if req.Username != nil {
+        if err := service.changeUsername(ctx, user, *req.Username); err != nil {
+            return nil, err
+        }
     }
 
     // 3. Save to database
     if err := service.userRepo.Update(ctx, user); err != nil {
+        if errors.Is(err, userRepo.ErrUsernameTaken) {
+            return nil, exceptions.NewConflictError("Username is already taken")
+        }
         return nil, exceptions.NewInternalServerError("Failed to update profile")
     }
 
+    service.loadPresence(ctx, user)
     return user, nil
 }
+
+// changeAvatar sets the profile picture: an external URL, or an image the user uploaded (by attachment ID).
+// Only an uploaded picture referenced by ID is visible to other users through GET /media/:id
+func (service *authServiceImpl) changeAvatar(ctx context.Context, user *domain.User, avatarURL, avatarID *string) error {
+	if avatarURL != nil && avatarID != nil {
+		return exceptions.NewBadRequestError("Send either avatar_url or avatar_id, not both")
+	}
+
+	if avatarURL != nil {
+		if domain.IsAttachmentURL(*avatarURL) {
+			return exceptions.NewBadRequestError("Use avatar_id to set an uploaded picture")
+		}
+		user.AvatarURL = avatarURL
+		user.AvatarAttachmentID = nil
+		return nil
+	}
+
+	attachment, err := service.attachmentRepo.FindByID(ctx, *avatarID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exceptions.NewInternalServerError("Failed to check avatar")
+	}
+	if attachment == nil || !attachment.CanBeAvatarOf(user.ID) {
+		return exceptions.NewBadRequestError("Avatar must be an image you uploaded")
+	}
+
+	user.AvatarURL = &attachment.URL
+	user.AvatarAttachmentID = &attachment.ID
+	return nil
+}
+
+// changeUsername validates a new username and sets it on the user (cooldown + uniqueness)
+func (service *authServiceImpl) changeUsername(ctx context.Context, user *domain.User, username string) error {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	if err := domain.ValidateUsername(username); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	// Same username (exact): nothing to do, and no cooldown used
+	if user.Username != nil && *user.Username == username {
+		return nil
+	}
+
+	// Changing only the case of the current username doesn't need the cooldown
+	caseOnly := user.Username != nil && strings.EqualFold(*user.Username, username)
+	if !caseOnly {
+		if next := user.NextUsernameChangeAt(); next != nil && time.Now().Before(*next) {
+			return exceptions.NewBadRequestError(fmt.Sprintf("You can change your username again after %s", next.UTC().Format(time.RFC3339)))
+		}
+
+		existing, err := service.userRepo.FindByUsername(ctx, username)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewInternalServerError("Failed to check username")
+		}
+		if existing != nil && existing.ID != user.ID {
+			return exceptions.NewConflictError("Username is already taken")
+		}
+
+		now := time.Now()
+		user.UsernameChangedAt = &now
+	}
+
+	user.Username = &username
+	return nil
+}
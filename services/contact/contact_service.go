@@ -0,0 +1,27 @@
+package contact
+
+import (
+	"chatapp-api/models/web"
+	"context"
+)
+
+// ContactService interface for contacts (requests, accept/decline, contact list)
+type ContactService interface {
+	// SendRequest sends a contact request (accepts it instead if the other user already sent one)
+	SendRequest(ctx context.Context, userID, targetUserID string) (*web.ContactRequestResponse, error)
+
+	// GetRequests lists pending requests received ("incoming") or sent ("outgoing") by the user
+	GetRequests(ctx context.Context, userID, direction string) ([]web.ContactRequestResponse, error)
+
+	// AcceptRequest accepts a request received by the user
+	AcceptRequest(ctx context.Context, userID, requestID string) (*web.ContactResponse, error)
+
+	// DeclineRequest declines a request received by the user, or cancels one they sent
+	DeclineRequest(ctx context.Context, userID, requestID string) error
+
+	// GetContacts lists the user's contacts with their presence
+	GetContacts(ctx context.Context, userID string, page, limit int) ([]web.ContactResponse, *web.PaginationMeta, error)
+
+	// RemoveContact removes a user from the contacts (for both users)
+	RemoveContact(ctx context.Context, userID, contactUserID string) error
+}
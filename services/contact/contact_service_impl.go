@@ -0,0 +1,380 @@
+package contact
+
+import (
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"chatapp-api/models/web"
+	contactRepo "chatapp-api/repositories/contact"
+	convRepo "chatapp-api/repositories/conversation"
+	presenceRepo "chatapp-api/repositories/presence"
+	userRepo "chatapp-api/repositories/user"
+	userBlockRepo "chatapp-api/repositories/user_block"
+	"chatapp-api/websocket"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// Directions of contact requests
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+// contactServiceImpl implements ContactService interface
+type contactServiceImpl struct {
+	contactRepo   contactRepo.ContactRepository
+	userRepo      userRepo.UserRepository
+	convRepo      convRepo.ConversationRepository
+	presenceRepo  presenceRepo.PresenceRepository
+	userBlockRepo userBlockRepo.UserBlockRepository
+	hub           *websocket.Hub
+}
+
+// NewContactService creates a new ContactService instance
+func NewContactService(
+	contactRepo contactRepo.ContactRepository,
+	userRepo userRepo.UserRepository,
+	convRepo convRepo.ConversationRepository,
+	presenceRepo presenceRepo.PresenceRepository,
+	userBlockRepo userBlockRepo.UserBlockRepository,
+	hub *websocket.Hub) ContactService {
+	return &contactServiceImpl{
+		contactRepo:   contactRepo,
+		userRepo:      userRepo,
+		convRepo:      convRepo,
+		presenceRepo:  presenceRepo,
+		userBlockRepo: userBlockRepo,
+		hub:           hub,
+	}
+}
+
+// SendRequest implements ContactService
+func (service *contactServiceImpl) SendRequest(ctx context.Context, userID, targetUserID string) (*web.ContactRequestResponse, error) {
+	// 1. Validation: Can't add yourself
+	if userID == targetUserID {
+		return nil, exceptions.NewBadRequestError("You can't add yourself as a contact")
+	}
+
+	// 2. Validation: Target user must exist
+	if _, err := service.userRepo.FindByID(ctx, targetUserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("User not found")
+		}
+		return nil, err
+	}
+
+	// 3. Validation: No requests between blocked users
+	blockedByMe, err := service.userBlockRepo.HasBlocked(ctx, userID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedByMe {
+		return nil, exceptions.NewBadRequestError("You have blocked this user, unblock them first")
+	}
+
+	blockedMe, err := service.userBlockRepo.HasBlocked(ctx, targetUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedMe {
+		return nil, exceptions.NewForbiddenError("You can't add this user as a contact")
+	}
+
+	// 4. Check the existing contact or request between the two users
+	existing, err := service.contactRepo.FindBetween(ctx, userID, targetUserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if existing != nil {
+		switch {
+		case existing.Status == domain.ContactStatusAccepted:
+			return nil, exceptions.NewConflictError("You are already contacts")
+		case existing.RequesterID == userID:
+			return nil, exceptions.NewConflictError("Contact request already sent")
+		}
+
+		// They already sent a request: sending one back accepts it
+		if err := service.accept(ctx, existing.ID); err != nil {
+			return nil, err
+		}
+
+		return service.findRequestResponse(ctx, existing.ID, userID)
+	}
+
+	// 5. Save the request
+	contact := &domain.Contact{
+		RequesterID: userID,
+		AddresseeID: targetUserID,
+		Status:      domain.ContactStatusPending,
+	}
+	if err := service.contactRepo.Create(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	// 6. Notify the addressee
+	created, err := service.contactRepo.FindByID(ctx, contact.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.loadPresence(ctx, &created.Requester, &created.Addressee)
+	notification := buildRequestResponse(created, targetUserID)
+	service.sendEvent(targetUserID, websocket.EventContactRequest, notification)
+
+	response := buildRequestResponse(created, userID)
+	return &response, nil
+}
+
+// GetRequests implements ContactService
+func (service *contactServiceImpl) GetRequests(ctx context.Context, userID, direction string) ([]web.ContactRequestResponse, error) {
+	// 1. Get pending requests (incoming by default)
+	var contacts []domain.Contact
+	var err error
+	if direction == DirectionOutgoing {
+		contacts, err = service.contactRepo.FindPendingOutgoing(ctx, userID)
+	} else {
+		contacts, err = service.contactRepo.FindPendingIncoming(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Load live presence of the other users
+	users := make([]*domain.User, len(contacts))
+	for idx := range contacts {
+		users[idx] = contacts[idx].OtherUser(userID)
+	}
+	service.loadPresence(ctx, users...)
+
+	// 3. Convert to response
+	result := make([]web.ContactRequestResponse, len(contacts))
+	for idx := range contacts {
+		result[idx] = buildRequestResponse(&contacts[idx], userID)
+	}
+
+	return result, nil
+}
+
+// AcceptRequest implements ContactService
+func (service *contactServiceImpl) AcceptRequest(ctx context.Context, userID, requestID string) (*web.ContactResponse, error) {
+	// 1. Find request
+	contact, err := service.findPendingRequest(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validation: Only the addressee can accept
+	if contact.AddresseeID != userID {
+		return nil, exceptions.NewForbiddenError("Only the receiver can accept a contact request")
+	}
+
+	// 3. Accept (and notify the requester)
+	if err := service.accept(ctx, contact.ID); err != nil {
+		return nil, err
+	}
+
+	// 4. Reload for response
+	accepted, err := service.contactRepo.FindByID(ctx, contact.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.loadPresence(ctx, accepted.OtherUser(userID))
+	response := buildContactResponse(accepted, userID)
+	return &response, nil
+}
+
+// DeclineRequest implements ContactService
+func (service *contactServiceImpl) DeclineRequest(ctx context.Context, userID, requestID string) error {
+	// 1. Find request (the addressee declines, the requester cancels)
+	contact, err := service.findPendingRequest(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+
+	// 2. Delete it (the requester isn't notified, they can send a new one later)
+	return service.contactRepo.Delete(ctx, contact.ID)
+}
+
+// GetContacts implements ContactService
+func (service *contactServiceImpl) GetContacts(ctx context.Context, userID string, page, limit int) ([]web.ContactResponse, *web.PaginationMeta, error) {
+	// 1. Set default pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// 2. Fetch contacts
+	contacts, total, err := service.contactRepo.FindAccepted(ctx, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 3. Load live presence of the contacts
+	users := make([]*domain.User, len(contacts))
+	for idx := range contacts {
+		users[idx] = contacts[idx].OtherUser(userID)
+	}
+	service.loadPresence(ctx, users...)
+
+	// 4. Convert to response
+	result := make([]web.ContactResponse, len(contacts))
+	for idx := range contacts {
+		result[idx] = buildContactResponse(&contacts[idx], userID)
+	}
+
+	pagination := &web.PaginationMeta{
+		CurrentPage: page,
+		PerPage:     limit,
+		TotalItems:  total,
+		TotalPages:  int(math.Ceil(float64(total) / float64(limit))),
+	}
+
+	return result, pagination, nil
+}
+
+// RemoveContact implements ContactService
+func (service *contactServiceImpl) RemoveContact(ctx context.Context, userID, contactUserID string) error {
+	// 1. Find the contact
+	contact, err := service.contactRepo.FindBetween(ctx, userID, contactUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("User is not in your contacts")
+		}
+		return err
+	}
+
+	if contact.Status != domain.ContactStatusAccepted {
+		return exceptions.NewNotFoundError("User is not in your contacts")
+	}
+
+	// 2. Remove it for both users
+	return service.contactRepo.Delete(ctx, contact.ID)
+}
+
+// accept accepts a pending request, moves a DM between the two users out of their message request inboxes
+// and notifies the requester
+func (service *contactServiceImpl) accept(ctx context.Context, contactID string) error {
+	contact, err := service.contactRepo.FindByID(ctx, contactID)
+	if err != nil {
+		return err
+	}
+
+	if err := service.contactRepo.Accept(ctx, contact); err != nil {
+		return err
+	}
+
+	// Contacts can DM each other, so a pending DM between them is accepted too
+	conv, err := service.convRepo.FindDirectConversation(ctx, contact.RequesterID, contact.AddresseeID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if conv != nil {
+		for _, userID := range []string{contact.RequesterID, contact.AddresseeID} {
+			if err := service.convRepo.SetMessageRequest(ctx, conv.ID, userID, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	service.loadPresence(ctx, &contact.Addressee)
+	service.sendEvent(contact.RequesterID, websocket.EventContactAccepted, buildContactResponse(contact, contact.RequesterID))
+	return nil
+}
+
+// findPendingRequest finds a pending request sent or received by the user
+func (service *contactServiceImpl) findPendingRequest(ctx context.Context, userID, requestID string) (*domain.Contact, error) {
+	contact, err := service.contactRepo.FindByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Contact request not found")
+		}
+		return nil, err
+	}
+
+	// Other users' requests are hidden (same as not existing)
+	if contact.RequesterID != userID && contact.AddresseeID != userID {
+		return nil, exceptions.NewNotFoundError("Contact request not found")
+	}
+
+	if contact.Status != domain.ContactStatusPending {
+		return nil, exceptions.NewBadRequestError("Contact request was already accepted")
+	}
+
+	return contact, nil
+}
+
+// findRequestResponse reloads a contact and converts it to a request response from the user's point of view
+func (service *contactServiceImpl) findRequestResponse(ctx context.Context, contactID, userID string) (*web.ContactRequestResponse, error) {
+	contact, err := service.contactRepo.FindByID(ctx, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.loadPresence(ctx, contact.OtherUser(userID))
+	response := buildRequestResponse(contact, userID)
+	return &response, nil
+}
+
+// loadPresence fills users' live presence from Redis
+// Presence is best effort: on failure users are shown as offline
+func (service *contactServiceImpl) loadPresence(ctx context.Context, users ...*domain.User) {
+	if err := service.presenceRepo.Load(ctx, users...); err != nil {
+		log.Printf("Failed to load presence: %v", err)
+	}
+}
+
+// sendEvent sends a WebSocket event to a user (if they are online)
+func (service *contactServiceImpl) sendEvent(userID, event string, data interface{}) {
+	if service.hub == nil {
+		return
+	}
+
+	jsonData, err := json.Marshal(websocket.WSMessage{
+		Event: event,
+		Data:  data,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s event: %v", event, err)
+		return
+	}
+
+	service.hub.SendToUser(userID, jsonData)
+}
+
+// buildContactResponse converts an accepted domain.Contact to web.ContactResponse from the user's point of view
+func buildContactResponse(contact *domain.Contact, userID string) web.ContactResponse {
+	response := web.ContactResponse{
+		User:  web.NewUserBrief(contact.OtherUser(userID), userID, true),
+		Since: contact.CreatedAt,
+	}
+	if contact.AcceptedAt != nil {
+		response.Since = *contact.AcceptedAt
+	}
+	return response
+}
+
+// buildRequestResponse converts domain.Contact to web.ContactRequestResponse from the user's point of view
+func buildRequestResponse(contact *domain.Contact, userID string) web.ContactRequestResponse {
+	direction := DirectionIncoming
+	if contact.RequesterID == userID {
+		direction = DirectionOutgoing
+	}
+
+	isContact := contact.Status == domain.ContactStatusAccepted
+	return web.ContactRequestResponse{
+		ID:        contact.ID,
+		User:      web.NewUserBrief(contact.OtherUser(userID), userID, isContact),
+		Direction: direction,
+		Status:    contact.Status,
+		CreatedAt: contact.CreatedAt,
+	}
+}
@@ -13,6 +13,15 @@ type ConversationService interface {
 	// GetConversations retrieves all conversations for a user (list view)
 	GetConversations(ctx context.Context, userID string) ([]web.ConversationListItem, error)
 
+	// GetMessageRequests retrieves DMs from non-contacts waiting in the user's message request inbox
+	GetMessageRequests(ctx context.Context, userID string) ([]web.ConversationListItem, error)
+
+	// AcceptMessageRequest moves a DM from the message request inbox to the conversation list
+	AcceptMessageRequest(ctx context.Context, userID, conversationID string) (*web.ConversationResponse, error)
+
+	// DeclineMessageRequest deletes a DM waiting in the message request inbox
+	DeclineMessageRequest(ctx context.Context, userID, conversationID string) error
+
 	// GetConversationByID retrieves a single conversation detail
 	GetConversationByID(ctx context.Context, userID, conversationID string) (*web.ConversationResponse, error)
 
@@ -22,9 +31,31 @@ type ConversationService interface {
 	// AddParticipants adds new participants to a group conversation
 	AddParticipants(ctx context.Context, userID, conversationID string, req *web.AddParticipantRequest) error
 
+	// AddMember adds a single user to a group without permission checks (invite links, approved join requests)
+	// actorID is the user who let them in (equal to userID when joining directly through an invite)
+	AddMember(ctx context.Context, actorID, conversationID, userID string) error
+
 	// LeaveConversation leaves a conversation
 	LeaveConversation(ctx context.Context, userID, conversationID string) error
 
 	// KickParticipant removes a participant from a conversation (amdin only)
 	KickParticipant(ctx context.Context, adminUserID, conversationID, targetUserID string) error
+
+	// UpdateParticipantRole changes a participant's role (owner/admin only)
+	UpdateParticipantRole(ctx context.Context, userID, conversationID, targetUserID string, req *web.UpdateParticipantRoleRequest) (*web.ConversationResponse, error)
+
+	// TransferOwnership makes another participant the owner of a group (owner only)
+	TransferOwnership(ctx context.Context, userID, conversationID string, req *web.TransferOwnershipRequest) (*web.ConversationResponse, error)
+
+	// BanParticipant bans a user from a group, removing them if they are still a participant
+	BanParticipant(ctx context.Context, userID, conversationID string, req *web.BanParticipantRequest) error
+
+	// UnbanParticipant lifts a user's ban from a group
+	UnbanParticipant(ctx context.Context, userID, conversationID, targetUserID string) error
+
+	// GetBans lists active bans of a group (owner/admin/moderator)
+	GetBans(ctx context.Context, userID, conversationID string) ([]web.BanResponse, error)
+
+	// GetModerationLog retrieves the moderation log of a group conversation (admin only)
+	GetModerationLog(ctx context.Context, userID, conversationID string, page, limit int) ([]web.ModerationLogResponse, *web.PaginationMeta, error)
 }
\ No newline at end of file
@@ -4,10 +4,23 @@ import (
 	"chatapp-api/exceptions"
 	"chatapp-api/models/domain"
 	"chatapp-api/models/web"
+	attachmentRepo "chatapp-api/repositories/attachment"
+	banRepo "chatapp-api/repositories/ban"
+	contactRepo "chatapp-api/repositories/contact"
 	convRepo "chatapp-api/repositories/conversation"
+	messageRepo "chatapp-api/repositories/message"
+	moderationLogRepo "chatapp-api/repositories/moderation_log"
+	presenceRepo "chatapp-api/repositories/presence"
 	userRepo "chatapp-api/repositories/user"
+	userBlockRepo "chatapp-api/repositories/user_block"
+	"chatapp-api/websocket"
 	"context"
+	"encoding/json"
 	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,29 +29,72 @@ import (
 type conversationServiceImpl struct {
 	convRepo convRepo.ConversationRepository	
 	userRepo userRepo.UserRepository
+	presenceRepo presenceRepo.PresenceRepository
+	messageRepo messageRepo.MessageRepository
+	moderationLogRepo moderationLogRepo.ModerationLogRepository
+	banRepo banRepo.BanRepository
+	userBlockRepo userBlockRepo.UserBlockRepository
+	contactRepo contactRepo.ContactRepository
+	attachmentRepo attachmentRepo.AttachmentRepository
+	hub *websocket.Hub
 }
 
 // NewConversationService makes a new ConversationService instance
-func NewConversationService(convRepo convRepo.ConversationRepository, userRepo userRepo.UserRepository) ConversationService {
+func NewConversationService(
+	convRepo convRepo.ConversationRepository,
+	userRepo userRepo.UserRepository,
+	presenceRepo presenceRepo.PresenceRepository,
+	messageRepo messageRepo.MessageRepository,
+	moderationLogRepo moderationLogRepo.ModerationLogRepository,
+	banRepo banRepo.BanRepository,
+	userBlockRepo userBlockRepo.UserBlockRepository,
+	contactRepo contactRepo.ContactRepository,
+	attachmentRepo attachmentRepo.AttachmentRepository,
+	hub *websocket.Hub) ConversationService {
 	return &conversationServiceImpl{convRepo: convRepo,
 		userRepo: userRepo, 
+		presenceRepo: presenceRepo,
+		messageRepo: messageRepo,
+		moderationLogRepo: moderationLogRepo,
+		banRepo: banRepo,
+		userBlockRepo: userBlockRepo,
+		contactRepo: contactRepo,
+		attachmentRepo: attachmentRepo,
+		hub: hub,
 	}
 }
 
 // CreateConversation implements ConversationService
 func (service *conversationServiceImpl) CreateConversation(ctx context.Context, userID string, req *web.CreateConversationRequest) (*web.ConversationResponse, error) {
 	// 1. Validation: For DM, only 1 participant allowed (interlocutor)
+	isMessageRequest := false
 	if req.Type == "direct" {
 		if len(req.ParticipantIDs) != 1 {
 			return nil, exceptions.NewBadRequestError("Direct message requires exactly 1 participant")
 		}
 
-		// Check if DM already exists between these two users
+		// Refuse DM if either side has blocked the other
 		targetUserID := req.ParticipantIDs[0]
+		if err := service.checkNotBlocked(ctx, userID, targetUserID); err != nil {
+			return nil, err
+		}
+
+		// Check if DM already exists between these two users
 		existingConv, err := service.convRepo.FindDirectConversation(ctx, userID, targetUserID)
 		if err == nil && existingConv != nil {
 			// DM already exists, return it
-			return service.buildConversationResponse(existingConv, userID), nil
+			// Opening it yourself accepts it if it was waiting in your message request inbox
+			if err := service.convRepo.SetMessageRequest(ctx, existingConv.ID, userID, false); err != nil {
+				return nil, err
+			}
+			service.loadConversationPresence(ctx, existingConv)
+			return service.buildConversationResponse(ctx, existingConv, userID), nil
+		}
+
+		// Users who only accept DMs from contacts get DMs from others in their message request inbox
+		isMessageRequest, err = service.needsMessageRequest(ctx, userID, targetUserID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -60,13 +116,17 @@ func (service *conversationServiceImpl) CreateConversation(ctx context.Context,
 		}
 	}
 
-	// 4. Build participants list (include creator as admin)
+	// 4. Build participants list (include creator as owner of the group)
 	participants := make([]domain.Participant, 0, len(req.ParticipantIDs)+1)
 
-	// Add creator as admin
+	// Add creator as owner (roles have no meaning in DM)
+	creatorRole := domain.RoleMember
+	if req.Type == "group" {
+		creatorRole = domain.RoleOwner
+	}
 	participants = append(participants, domain.Participant{
 		UserID: userID,
-		Role: "admin",
+		Role: creatorRole,
 	})
 
 	// Add other participants as member
@@ -77,7 +137,8 @@ func (service *conversationServiceImpl) CreateConversation(ctx context.Context,
 		}
 		participants = append(participants, domain.Participant{
 			UserID: participantID,
-			Role: "member",
+			Role: domain.RoleMember,
+			IsMessageRequest: isMessageRequest,
 		})
 	}
 
@@ -98,8 +159,22 @@ func (service *conversationServiceImpl) CreateConversation(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	service.loadConversationPresence(ctx, createdConv)
 
-	return service.buildConversationResponse(createdConv, userID), nil
+	// 7. Notify the other participants that a new conversation appeared
+	var otherIDs []string
+	for _, participant := range createdConv.Participants {
+		if participant.UserID != userID {
+			otherIDs = append(otherIDs, participant.UserID)
+		}
+	}
+	event := websocket.EventConversationCreated
+	if isMessageRequest {
+		event = websocket.EventMessageRequest
+	}
+	service.sendConversationEvent(ctx, createdConv, otherIDs, event)
+
+	return service.buildConversationResponse(ctx, createdConv, userID), nil
 }
 
 // GetConversations implements ConversationService
@@ -110,13 +185,73 @@ func (service *conversationServiceImpl) GetConversations(ctx context.Context, us
 		return nil, err
 	}
 
+	return service.buildConversationList(ctx, conversations, userID), nil
+}
+
+// GetMessageRequests implements ConversationService
+func (service *conversationServiceImpl) GetMessageRequests(ctx context.Context, userID string) ([]web.ConversationListItem, error) {
+	// 1. Get DMs waiting in the user's message request inbox
+	conversations, err := service.convRepo.FindMessageRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.buildConversationList(ctx, conversations, userID), nil
+}
+
+// AcceptMessageRequest implements ConversationService
+func (service *conversationServiceImpl) AcceptMessageRequest(ctx context.Context, userID, conversationID string) (*web.ConversationResponse, error) {
+	// 1. Validation: Conversation must be in the user's message request inbox
+	if _, err := service.findMessageRequest(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	// 2. Move it to the conversation list
+	if err := service.convRepo.SetMessageRequest(ctx, conversationID, userID, false); err != nil {
+		return nil, err
+	}
+
+	// 3. Reload conversation for response
+	updatedConv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.loadConversationPresence(ctx, updatedConv)
+	return service.buildConversationResponse(ctx, updatedConv, userID), nil
+}
+
+// DeclineMessageRequest implements ConversationService
+func (service *conversationServiceImpl) DeclineMessageRequest(ctx context.Context, userID, conversationID string) error {
+	// 1. Validation: Conversation must be in the user's message request inbox
+	conv, err := service.findMessageRequest(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	// 2. Delete the DM (the sender isn't notified)
+	return service.convRepo.Delete(ctx, conv.ID)
+}
+
+// buildConversationList loads live presence of last message senders and converts conversations to list items
+func (service *conversationServiceImpl) buildConversationList(ctx context.Context, conversations []domain.Conversation, userID string) []web.ConversationListItem {
+	// 1. Load live presence of last message senders
+	senders := make([]*domain.User, 0, len(conversations))
+	for idx := range conversations {
+		if len(conversations[idx].Messages) > 0 {
+			senders = append(senders, &conversations[idx].Messages[0].Sender)
+		}
+	}
+	service.loadPresence(ctx, senders...)
+
 	// 2. Convert every single conversation to ConversationListItem
+	contactIDs := service.contactSet(ctx, userID)
 	result := make([]web.ConversationListItem, len(conversations))
 	for idx, conv := range conversations {
-		result[idx] = service.buildConversationListItem(&conv, userID)
+		result[idx] = service.buildConversationListItem(&conv, userID, contactIDs)
 	}
 
-	return result, nil
+	return result
 }
 
 // GetConversationByID implements ConversationService
@@ -144,7 +279,8 @@ func (service *conversationServiceImpl) GetConversationByID(ctx context.Context,
 	}
 
 	// 3. Return conversation response
-	return service.buildConversationResponse(conversation, userID), nil
+	service.loadConversationPresence(ctx, conversation)
+	return service.buildConversationResponse(ctx, conversation, userID), nil
 }
 
 // UpdateConversation implements ConversationService
@@ -163,38 +299,93 @@ func (service *conversationServiceImpl) UpdateConversation(ctx context.Context,
 		return nil, exceptions.NewBadRequestError("Cannot update direct message conversation")
 	}
 
-	// 3. Validation: User must be an admin
-	isAdmin := false
+	// 3. Validation: User must have permission to update the conversation
+	canUpdate := false
 	for _, participant := range conv.Participants {
-		if participant.UserID == userID && participant.Role == "admin" {
-			isAdmin = true
+		if participant.UserID == userID && domain.HasPermission(participant.Role, domain.PermissionUpdateConversation) {
+			canUpdate = true
 			break
 		}
 	}
 
-	if !isAdmin {
-		return nil, exceptions.NewForbiddenError("Only admin can update conversation")
+	if !canUpdate {
+		return nil, exceptions.NewForbiddenError("Only owner or admin can update conversation")
 	}
 
 	// 4. Update the fields sent
+	nameChanged := req.Name != nil && (conv.Name == nil || *conv.Name != *req.Name)
 	if req.Name != nil {
 		conv.Name = req.Name
 	}
-	if req.AvatarURL != nil {
-		conv.AvatarURL = req.AvatarURL
+
+	previousAvatar := conv.AvatarURL
+	if req.AvatarURL != nil || req.AvatarID != nil {
+		if err := service.changeAvatar(ctx, conv, userID, req.AvatarURL, req.AvatarID); err != nil {
+			return nil, err
+		}
 	}
+	avatarChanged := conv.AvatarURL != nil && (previousAvatar == nil || *previousAvatar != *conv.AvatarURL)
 	
 	if err := service.convRepo.Update(ctx, conv); err != nil {
 		return nil, err
 	}
 
-	// 5. Reload and return
+	// 5. Post system messages for the changes
+	actorName := participantName(conv, userID)
+	if nameChanged {
+		service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+			Action: domain.SystemActionConversationRenamed,
+			Actor: userID,
+			Name: req.Name,
+		}, actorName+" renamed the group to \""+*req.Name+"\"")
+	}
+	if avatarChanged {
+		service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+			Action: domain.SystemActionAvatarChanged,
+			Actor: userID,
+			AvatarURL: conv.AvatarURL,
+		}, actorName+" changed the group photo")
+	}
+
+	// 6. Reload, notify participants and return
 	updatedConv, err := service.convRepo.FindByID(ctx, conversationID)
 	if err != nil {
 		return nil, err
 	}
+	service.loadConversationPresence(ctx, updatedConv)
 
-	return service.buildConversationResponse(updatedConv, userID), nil
+	service.sendConversationEvent(ctx, updatedConv, participantIDs(updatedConv), websocket.EventConversationUpdated)
+
+	return service.buildConversationResponse(ctx, updatedConv, userID), nil
+}
+
+// changeAvatar sets the group picture: an external URL, or an image the user uploaded (by attachment ID).
+// Only an uploaded picture referenced by ID is visible to the participants through GET /media/:id
+func (service *conversationServiceImpl) changeAvatar(ctx context.Context, conv *domain.Conversation, userID string, avatarURL, avatarID *string) error {
+	if avatarURL != nil && avatarID != nil {
+		return exceptions.NewBadRequestError("Send either avatar_url or avatar_id, not both")
+	}
+
+	if avatarURL != nil {
+		if domain.IsAttachmentURL(*avatarURL) {
+			return exceptions.NewBadRequestError("Use avatar_id to set an uploaded picture")
+		}
+		conv.AvatarURL = avatarURL
+		conv.AvatarAttachmentID = nil
+		return nil
+	}
+
+	attachment, err := service.attachmentRepo.FindByID(ctx, *avatarID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if attachment == nil || !attachment.CanBeAvatarOf(userID) {
+		return exceptions.NewBadRequestError("Avatar must be an image you uploaded")
+	}
+
+	conv.AvatarURL = &attachment.URL
+	conv.AvatarAttachmentID = &attachment.ID
+	return nil
 }
 
 // AddParticipants implements ConversationService
@@ -213,17 +404,17 @@ func (service *conversationServiceImpl) AddParticipants(ctx context.Context, use
 		return exceptions.NewBadRequestError("Cannot add participants to direct message")
 	}
 
-	// 3. Validation: User must be an admin
-	isAdmin := false
+	// 3. Validation: User must have permission to add participants
+	canAdd := false
 	for _, participant := range conv.Participants {
-		if participant.UserID == userID && participant.Role == "admin" {
-			isAdmin = true
+		if participant.UserID == userID && domain.HasPermission(participant.Role, domain.PermissionAddParticipants) {
+			canAdd = true
 			break
 		}
 	}
 
-	if !isAdmin {
-		return exceptions.NewForbiddenError("Only admin can add participants")
+	if !canAdd {
+		return exceptions.NewForbiddenError("Only owner or admin can add participants")
 	}
 
 	// 4. Create map existing participants for check duplication
@@ -232,15 +423,16 @@ func (service *conversationServiceImpl) AddParticipants(ctx context.Context, use
 		existingParticipants[participant.UserID] = true
 	}
 
-	// 5. Add new participants
+	// 5. Validate every new user before adding anyone: must exist and not be banned from this group
+	var newParticipants []domain.Participant
+	var addedIDs, addedNames []string
 	for _, newUserID :=  range req.UserIDs {
-		// Skip if user is already a participant
+		// Skip if user is already a participant (or listed twice)
 		if existingParticipants[newUserID] {
 			continue
 		}
 
-		// Validation: user must exist in database
-		_, err := service.userRepo.FindByID(ctx, newUserID)
+		newUser, err := service.userRepo.FindByID(ctx, newUserID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return exceptions.NewNotFoundError("User " + newUserID + " not found")
@@ -248,21 +440,120 @@ func (service *conversationServiceImpl) AddParticipants(ctx context.Context, use
 			return err
 		}
 
-		// Add as member
-		newParticipant := &domain.Participant{
+		if err := service.checkNotBanned(ctx, conversationID, newUserID); err != nil {
+			return err
+		}
+
+		newParticipants = append(newParticipants, domain.Participant{
 			UserID: newUserID,
 			ConversationID: conversationID,
-			Role: "member",
+			Role: domain.RoleMember,
+		})
+		existingParticipants[newUserID] = true
+		addedIDs = append(addedIDs, newUserID)
+		addedNames = append(addedNames, newUser.Name)
+	}
+
+	if len(newParticipants) == 0 {
+		return nil
+	}
+
+	// 6. Add them all as members at once
+	if err := service.convRepo.AddParticipants(ctx, newParticipants); err != nil {
+		return err
+	}
+
+	// 7. Notify participants and post one system message
+	service.announceNewMembers(ctx, conv, userID, addedIDs, addedNames)
+
+	return nil
+}
+
+// AddMember implements ConversationService
+func (service *conversationServiceImpl) AddMember(ctx context.Context, actorID, conversationID, userID string) error {
+	// 1. Find conversation
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("Conversation not found")
 		}
+		return err
+	}
 
-		if err := service.convRepo.AddParticipant(ctx, newParticipant); err != nil {
-			return err
+	// 2. Validation: Only group can have new members
+	if conv.Type != "group" {
+		return exceptions.NewBadRequestError("Cannot add participants to direct message")
+	}
+
+	// 3. Validation: User must not be a participant yet
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID {
+			return exceptions.NewConflictError("User is already a participant")
 		}
 	}
 
+	// 4. Validation: user must exist in database and not be banned
+	newUser, err := service.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("User " + userID + " not found")
+		}
+		return err
+	}
+
+	if err := service.checkNotBanned(ctx, conversationID, userID); err != nil {
+		return err
+	}
+
+	// 5. Add as member
+	newParticipant := &domain.Participant{
+		UserID: userID,
+		ConversationID: conversationID,
+		Role: domain.RoleMember,
+	}
+
+	if err := service.convRepo.AddParticipant(ctx, newParticipant); err != nil {
+		return err
+	}
+
+	// 6. Notify participants and post system message
+	service.announceNewMembers(ctx, conv, actorID, []string{userID}, []string{newUser.Name})
+
 	return nil
 }
 
+// announceNewMembers notifies existing participants, gives new members the conversation
+// and posts the system message (conv holds the participants from before the change)
+func (service *conversationServiceImpl) announceNewMembers(ctx context.Context, conv *domain.Conversation, actorID string, addedIDs, addedNames []string) {
+	// 1. Notify existing participants, and give new members the conversation
+	service.sendEvent(participantIDs(conv), conv.ID, websocket.EventParticipantAdded, websocket.ParticipantAddedData{
+		UserIDs: addedIDs,
+		ActorID: actorID,
+	})
+
+	if updatedConv, err := service.convRepo.FindByID(ctx, conv.ID); err == nil {
+		service.loadConversationPresence(ctx, updatedConv)
+		service.sendConversationEvent(ctx, updatedConv, addedIDs, websocket.EventConversationCreated)
+	}
+
+	// 2. Post system message (new members receive it too)
+	// A user who let themselves in (invite link) "joined", otherwise they were "added"
+	if len(addedIDs) == 1 && addedIDs[0] == actorID {
+		service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+			Action: domain.SystemActionMemberJoined,
+			Actor: actorID,
+			Targets: addedIDs,
+		}, addedNames[0]+" joined via invite link")
+		return
+	}
+
+	service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+		Action: domain.SystemActionMemberAdded,
+		Actor: actorID,
+		Targets: addedIDs,
+	}, participantName(conv, actorID)+" added "+strings.Join(addedNames, ", "))
+}
+
 // LeaveConversation implements ConversationService
 func (service *conversationServiceImpl) LeaveConversation(ctx context.Context, userID, conversationID string) error {
 	// 1. Find conversation
@@ -292,25 +583,25 @@ func (service *conversationServiceImpl) LeaveConversation(ctx context.Context, u
 		return exceptions.NewForbiddenError("You are not a participant of this conversation")
 	}
 
-	// 4. If user is the only admin, promote another member become admin
-	if userParticipant.Role == "admin" {
-		// Count the number of admins
-		adminCount := 0 
+	// 4. Promote a successor so the group is never left without an owner or admin
+	// Owner leaving: ownership goes to the successor
+	// Last admin leaving (no owner left): successor becomes admin
+	if userParticipant.Role == domain.RoleOwner || userParticipant.Role == domain.RoleAdmin {
+		managerCount := 0
 		for _, participant := range conv.Participants {
-			if participant.Role == "admin" {
-				adminCount++
+			if participant.UserID != userID && domain.RoleRank(participant.Role) >= domain.RoleRank(domain.RoleAdmin) {
+				managerCount++
 			}
 		}
 
-		// If there is only one admin (this user), promote another member become admin
-		if adminCount == 1 {
-			for _, participant := range conv.Participants {
-				if participant.UserID != userID && participant.Role == "member" {
-					participant.Role = "admin"
-					if err := service.convRepo.Update(ctx, conv); err != nil {
-						return err
-					}
-					break
+		if userParticipant.Role == domain.RoleOwner || managerCount == 0 {
+			if successor := findSuccessor(conv.Participants, userID); successor != nil {
+				newRole := domain.RoleAdmin
+				if userParticipant.Role == domain.RoleOwner {
+					newRole = domain.RoleOwner
+				}
+				if err := service.convRepo.UpdateParticipantRole(ctx, conversationID, successor.UserID, newRole); err != nil {
+					return err
 				}
 			}
 		}
@@ -321,6 +612,15 @@ func (service *conversationServiceImpl) LeaveConversation(ctx context.Context, u
 		return err
 	}
 
+	// 6. Notify remaining participants and the user's own client
+	service.notifyParticipantRemoved(conv, userID, userID, "left")
+
+	// 7. Post system message
+	service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+		Action: domain.SystemActionMemberLeft,
+		Actor: userID,
+	}, participantName(conv, userID)+" left the group")
+
 	return nil
 }
 
@@ -340,17 +640,17 @@ func (service *conversationServiceImpl) KickParticipant(ctx context.Context, adm
 		return exceptions.NewBadRequestError("Cannot kick participant from direct message")
 	}
 
-	// 3. Validation: User must be an admin
-	isAdmin := false
-	for _, participant := range conv.Participants {
-		if participant.UserID == adminUserID && participant.Role == "admin" {
-			isAdmin = true
+	// 3. Validation: User must have permission to kick participants
+	var adminParticipant *domain.Participant
+	for idx, participant := range conv.Participants {
+		if participant.UserID == adminUserID {
+			adminParticipant = &conv.Participants[idx]
 			break
 		}
 	}
 
-	if !isAdmin {
-		return exceptions.NewForbiddenError("Only admin can kick participants")
+	if adminParticipant == nil || !domain.HasPermission(adminParticipant.Role, domain.PermissionKickParticipants) {
+		return exceptions.NewForbiddenError("Only owner, admin or moderator can kick participants")
 	}
 
 	// 4. Validation: Cannot kick yourself (Use LeaveConversation instead)
@@ -371,9 +671,9 @@ func (service *conversationServiceImpl) KickParticipant(ctx context.Context, adm
 		return exceptions.NewNotFoundError("Target user is not a participant")
 	}
 
-	// 6. Validation: Cannot kick another admin
-	if targetParticipant.Role == "admin" {
-		return exceptions.NewConflictError("Cannot kick another admin")
+	// 6. Validation: Can only kick participants with a lower role
+	if domain.RoleRank(targetParticipant.Role) >= domain.RoleRank(adminParticipant.Role) {
+		return exceptions.NewForbiddenError("Cannot kick a participant with an equal or higher role")
 	}
 
 	// 7. Remove target from participant
@@ -381,12 +681,398 @@ func (service *conversationServiceImpl) KickParticipant(ctx context.Context, adm
 		return err
 	}
 
+	// 8. Notify remaining participants and the kicked user
+	service.notifyParticipantRemoved(conv, targetUserID, adminUserID, "kicked")
+
+	// 9. Post system message (the kicked user receives it too)
+	service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+		Action: domain.SystemActionMemberRemoved,
+		Actor: adminUserID,
+		Targets: []string{targetUserID},
+	}, participantName(conv, adminUserID)+" removed "+participantName(conv, targetUserID))
+
 	return nil
 }
 
 
+// UpdateParticipantRole implements ConversationService
+func (service *conversationServiceImpl) UpdateParticipantRole(ctx context.Context, userID, conversationID, targetUserID string, req *web.UpdateParticipantRoleRequest) (*web.ConversationResponse, error) {
+	// 1. Find conversation
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	// 2. Validation: Only group has roles
+	if conv.Type != "group" {
+		return nil, exceptions.NewBadRequestError("Cannot change roles in direct message")
+	}
+
+	// 3. Validation: Cannot change your own role
+	if targetUserID == userID {
+		return nil, exceptions.NewBadRequestError("Cannot change your own role")
+	}
+
+	// 4. Find actor and target participants
+	var actorParticipant, targetParticipant *domain.Participant
+	for idx, participant := range conv.Participants {
+		switch participant.UserID {
+		case userID:
+			actorParticipant = &conv.Participants[idx]
+		case targetUserID:
+			targetParticipant = &conv.Participants[idx]
+		}
+	}
+
+	if actorParticipant == nil || !domain.HasPermission(actorParticipant.Role, domain.PermissionManageRoles) {
+		return nil, exceptions.NewForbiddenError("Only owner or admin can change roles")
+	}
+
+	if targetParticipant == nil {
+		return nil, exceptions.NewNotFoundError("Target user is not a participant")
+	}
+
+	// 5. Validation: Can only manage participants and assign roles below your own rank
+	// Ownership can only be given through transfer ownership
+	actorRank := domain.RoleRank(actorParticipant.Role)
+	if domain.RoleRank(targetParticipant.Role) >= actorRank {
+		return nil, exceptions.NewForbiddenError("Cannot change the role of a participant with an equal or higher role")
+	}
+	if domain.RoleRank(req.Role) >= actorRank {
+		return nil, exceptions.NewForbiddenError("Cannot assign a role equal to or higher than your own")
+	}
+
+	// 6. Update role
+	if targetParticipant.Role != req.Role {
+		if err := service.convRepo.UpdateParticipantRole(ctx, conversationID, targetUserID, req.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7. Reload, notify participants and return
+	updatedConv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	service.loadConversationPresence(ctx, updatedConv)
+
+	service.sendConversationEvent(ctx, updatedConv, participantIDs(updatedConv), websocket.EventConversationUpdated)
+
+	return service.buildConversationResponse(ctx, updatedConv, userID), nil
+}
+
+// TransferOwnership implements ConversationService
+func (service *conversationServiceImpl) TransferOwnership(ctx context.Context, userID, conversationID string, req *web.TransferOwnershipRequest) (*web.ConversationResponse, error) {
+	// 1. Find conversation
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	// 2. Validation: Only group has an owner
+	if conv.Type != "group" {
+		return nil, exceptions.NewBadRequestError("Cannot transfer ownership of direct message")
+	}
+
+	// 3. Validation: Cannot transfer to yourself
+	if req.UserID == userID {
+		return nil, exceptions.NewBadRequestError("You are already the owner")
+	}
+
+	// 4. Validation: Only the owner can transfer ownership, and the target must be a participant
+	isOwner := false
+	isTargetParticipant := false
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID && domain.HasPermission(participant.Role, domain.PermissionTransferOwnership) {
+			isOwner = true
+		}
+		if participant.UserID == req.UserID {
+			isTargetParticipant = true
+		}
+	}
+
+	if !isOwner {
+		return nil, exceptions.NewForbiddenError("Only owner can transfer ownership")
+	}
+
+	if !isTargetParticipant {
+		return nil, exceptions.NewNotFoundError("Target user is not a participant")
+	}
+
+	// 5. Transfer: target becomes owner, current owner becomes admin
+	if err := service.convRepo.TransferOwnership(ctx, conversationID, userID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	// 6. Reload, notify participants and return
+	updatedConv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	service.loadConversationPresence(ctx, updatedConv)
+
+	service.sendConversationEvent(ctx, updatedConv, participantIDs(updatedConv), websocket.EventConversationUpdated)
+
+	return service.buildConversationResponse(ctx, updatedConv, userID), nil
+}
+
+// BanParticipant implements ConversationService
+func (service *conversationServiceImpl) BanParticipant(ctx context.Context, userID, conversationID string, req *web.BanParticipantRequest) error {
+	// 1. Find conversation
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("Conversation not found")
+		}
+		return err
+	}
+
+	// 2. Validation: Only group has a ban list
+	if conv.Type != "group" {
+		return exceptions.NewBadRequestError("Cannot ban users from direct message")
+	}
+
+	// 3. Validation: Cannot ban yourself
+	if req.UserID == userID {
+		return exceptions.NewBadRequestError("Cannot ban yourself")
+	}
+
+	// 4. Find actor and target participants
+	var actorParticipant, targetParticipant *domain.Participant
+	for idx, participant := range conv.Participants {
+		switch participant.UserID {
+		case userID:
+			actorParticipant = &conv.Participants[idx]
+		case req.UserID:
+			targetParticipant = &conv.Participants[idx]
+		}
+	}
+
+	if actorParticipant == nil || !domain.HasPermission(actorParticipant.Role, domain.PermissionBanParticipants) {
+		return exceptions.NewForbiddenError("Only owner, admin or moderator can ban users")
+	}
+
+	// 5. Validation: Target must exist, and if still in the group, have a lower role
+	if _, err := service.userRepo.FindByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("User " + req.UserID + " not found")
+		}
+		return err
+	}
+
+	if targetParticipant != nil && domain.RoleRank(targetParticipant.Role) >= domain.RoleRank(actorParticipant.Role) {
+		return exceptions.NewForbiddenError("Cannot ban a participant with an equal or higher role")
+	}
+
+	// 6. Save ban (banning again updates reason and expiry)
+	ban := &domain.ConversationBan{
+		ConversationID: conversationID,
+		UserID: req.UserID,
+		BannedBy: userID,
+		Reason: req.Reason,
+		CreatedAt: time.Now(),
+	}
+	if req.ExpiresInHours != nil {
+		expiresAt := time.Now().Add(time.Duration(*req.ExpiresInHours) * time.Hour)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	if err := service.banRepo.Upsert(ctx, ban); err != nil {
+		return err
+	}
+
+	// 7. Record in moderation log
+	if err := service.moderationLogRepo.Create(ctx, &domain.ModerationLog{
+		ConversationID: conversationID,
+		ActorID: userID,
+		TargetUserID: req.UserID,
+		Action: domain.ModerationActionBanUser,
+		Reason: req.Reason,
+	}); err != nil {
+		return err
+	}
+
+	// 8. Remove the user from the group if still a participant
+	if targetParticipant != nil {
+		if err := service.convRepo.RemoveParticipant(ctx, conversationID, req.UserID); err != nil {
+			return err
+		}
+
+		service.notifyParticipantRemoved(conv, req.UserID, userID, "banned")
+		service.createSystemMessage(ctx, conv, &domain.SystemPayload{
+			Action: domain.SystemActionMemberRemoved,
+			Actor: userID,
+			Targets: []string{req.UserID},
+		}, participantName(conv, userID)+" removed "+participantName(conv, req.UserID))
+	}
+
+	return nil
+}
+
+// UnbanParticipant implements ConversationService
+func (service *conversationServiceImpl) UnbanParticipant(ctx context.Context, userID, conversationID, targetUserID string) error {
+	// 1. Validation: User must be allowed to manage bans
+	if _, err := service.findGroupWithPermission(ctx, userID, conversationID, domain.PermissionBanParticipants); err != nil {
+		return err
+	}
+
+	// 2. Lift the ban
+	deleted, err := service.banRepo.Delete(ctx, conversationID, targetUserID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return exceptions.NewNotFoundError("User is not banned from this conversation")
+	}
+
+	// 3. Record in moderation log
+	return service.moderationLogRepo.Create(ctx, &domain.ModerationLog{
+		ConversationID: conversationID,
+		ActorID: userID,
+		TargetUserID: targetUserID,
+		Action: domain.ModerationActionUnbanUser,
+	})
+}
+
+// GetBans implements ConversationService
+func (service *conversationServiceImpl) GetBans(ctx context.Context, userID, conversationID string) ([]web.BanResponse, error) {
+	// 1. Validation: User must be allowed to manage bans
+	if _, err := service.findGroupWithPermission(ctx, userID, conversationID, domain.PermissionBanParticipants); err != nil {
+		return nil, err
+	}
+
+	// 2. Get active bans
+	bans, err := service.banRepo.FindActiveByConversationID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Find the user's contacts (for presence visibility) and load live presence
+	contactIDs, err := service.findContactIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, len(bans))
+	for idx := range bans {
+		users[idx] = &bans[idx].User
+	}
+	service.loadPresence(ctx, users...)
+
+	// 4. Convert to response
+	result := make([]web.BanResponse, len(bans))
+	for idx, ban := range bans {
+		result[idx] = web.BanResponse{
+			User: web.NewUserBrief(&ban.User, userID, contactIDs[ban.UserID]),
+			BannedBy: ban.BannedBy,
+			Reason: ban.Reason,
+			ExpiresAt: ban.ExpiresAt,
+			CreatedAt: ban.CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// GetModerationLog implements ConversationService
+func (service *conversationServiceImpl) GetModerationLog(ctx context.Context, userID, conversationID string, page, limit int) ([]web.ModerationLogResponse, *web.PaginationMeta, error) {
+	// 1. Find conversation
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, nil, err
+	}
+
+	// 2. Validation: Only group has a moderation log
+	if conv.Type != "group" {
+		return nil, nil, exceptions.NewBadRequestError("Direct message has no moderation log")
+	}
+
+	// 3. Validation: User must have permission to view the moderation log
+	canView := false
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID && domain.HasPermission(participant.Role, domain.PermissionViewModerationLog) {
+			canView = true
+			break
+		}
+	}
+
+	if !canView {
+		return nil, nil, exceptions.NewForbiddenError("Only owner, admin or moderator can view moderation log")
+	}
+
+	// 4. Set default pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// 5. Fetch log entries and total count
+	logs, err := service.moderationLogRepo.FindByConversationID(ctx, conversationID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, err := service.moderationLogRepo.CountByConversationID(ctx, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 6. Find the user's contacts (for presence visibility) and load live presence
+	contactIDs, err := service.findContactIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users := make([]*domain.User, 0, len(logs)*2)
+	for idx := range logs {
+		users = append(users, &logs[idx].Actor, &logs[idx].TargetUser)
+	}
+	service.loadPresence(ctx, users...)
+
+	// 7. Convert to response
+	result := make([]web.ModerationLogResponse, len(logs))
+	for idx, entry := range logs {
+		result[idx] = web.ModerationLogResponse{
+			ID: entry.ID,
+			Action: entry.Action,
+			Actor: web.NewUserBrief(&entry.Actor, userID, contactIDs[entry.Actor.ID]),
+			TargetUser: web.NewUserBrief(&entry.TargetUser, userID, contactIDs[entry.TargetUser.ID]),
+			MessageID: entry.MessageID,
+			Reason: entry.Reason,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	pagination := &web.PaginationMeta{
+		CurrentPage: page,
+		PerPage: limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+
+	return result, pagination, nil
+}
+
+
 // buildConversationResponse converts domain.Conversation to web.ConversationResponse
-func (s *conversationServiceImpl) buildConversationResponse(conv *domain.Conversation, currentUserID string) *web.ConversationResponse {
+func (s *conversationServiceImpl) buildConversationResponse(ctx context.Context, conv *domain.Conversation, currentUserID string) *web.ConversationResponse {
+	return s.buildConversationResponseFor(conv, currentUserID, s.contactSet(ctx, currentUserID))
+}
+
+// buildConversationResponseFor converts domain.Conversation to web.ConversationResponse for a viewer whose contacts are known
+func (s *conversationServiceImpl) buildConversationResponseFor(conv *domain.Conversation, currentUserID string, contactIDs map[string]bool) *web.ConversationResponse {
 	// Initialize
 	participants := make([]web.ParticipantResponse, len(conv.Participants))
 	var displayName string
@@ -396,12 +1082,7 @@ func (s *conversationServiceImpl) buildConversationResponse(conv *domain.Convers
 	for idx, participant := range conv.Participants {
 		// Convert to DTO
 		participants[idx] = web.ParticipantResponse{
-			User: web.UserBriefResponse{
-				ID:        participant.User.ID,
-				Name:      participant.User.Name,
-				AvatarURL: participant.User.AvatarURL,
-				IsOnline:  participant.User.IsOnline,
-			},
+			User: web.NewUserBrief(&participant.User, currentUserID, contactIDs[participant.UserID]),
 			Role:     participant.Role,
 			JoinedAt: participant.JoinedAt,
 		}
@@ -431,12 +1112,7 @@ func (s *conversationServiceImpl) buildConversationResponse(conv *domain.Convers
 			ID:      msg.ID,
 			Content: msg.Content,
 			Type:    msg.Type,
-			Sender: web.UserBriefResponse{
-				ID:        msg.Sender.ID,
-				Name:      msg.Sender.Name,
-				AvatarURL: msg.Sender.AvatarURL,
-				IsOnline:  msg.Sender.IsOnline,
-			},
+			Sender: web.NewUserBrief(&msg.Sender, currentUserID, contactIDs[msg.SenderID]),
 			CreatedAt: msg.CreatedAt,
 		}
 	}
@@ -457,7 +1133,7 @@ func (s *conversationServiceImpl) buildConversationResponse(conv *domain.Convers
 }
 
 // buildConversationListItem converts domain.Conversation to web.ConversationListItem
-func (service *conversationServiceImpl) buildConversationListItem(conv *domain.Conversation, currentUserID string) web.ConversationListItem {
+func (service *conversationServiceImpl) buildConversationListItem(conv *domain.Conversation, currentUserID string, contactIDs map[string]bool) web.ConversationListItem {
 	var displayName string
 	var displayAvatar *string
 
@@ -485,12 +1161,7 @@ func (service *conversationServiceImpl) buildConversationListItem(conv *domain.C
 			ID: msg.ID,
 			Content: msg.Content,
 			Type: msg.Type,
-			Sender: web.UserBriefResponse{
-				ID: msg.Sender.ID,
-				Name: msg.Sender.Name,
-				AvatarURL: msg.Sender.AvatarURL,
-				IsOnline: msg.Sender.IsOnline,
-			},
+			Sender: web.NewUserBrief(&msg.Sender, currentUserID, contactIDs[msg.SenderID]),
 			CreatedAt: msg.CreatedAt,
 		}
 	}
@@ -504,4 +1175,306 @@ func (service *conversationServiceImpl) buildConversationListItem(conv *domain.C
 		UnreadCount:   0, // TODO: implement unread count logic
 		UpdatedAt:     conv.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// loadPresence fills users' live presence from Redis
+// Presence is best effort: on failure users are shown as offline
+func (service *conversationServiceImpl) loadPresence(ctx context.Context, users ...*domain.User) {
+	if err := service.presenceRepo.Load(ctx, users...); err != nil {
+		log.Printf("Failed to load presence: %v", err)
+	}
+}
+
+// loadConversationPresence fills live presence of a conversation's participants and last message sender
+func (service *conversationServiceImpl) loadConversationPresence(ctx context.Context, conv *domain.Conversation) {
+	users := make([]*domain.User, 0, len(conv.Participants)+1)
+	for idx := range conv.Participants {
+		users = append(users, &conv.Participants[idx].User)
+	}
+	if len(conv.Messages) > 0 {
+		users = append(users, &conv.Messages[0].Sender)
+	}
+	service.loadPresence(ctx, users...)
+}
+
+// findSuccessor picks who takes over when a manager leaves:
+// the highest-ranked remaining participant, the longest member on ties
+func findSuccessor(participants []domain.Participant, leavingUserID string) *domain.Participant {
+	var successor *domain.Participant
+	for idx, participant := range participants {
+		if participant.UserID == leavingUserID {
+			continue
+		}
+		if successor == nil ||
+			domain.RoleRank(participant.Role) > domain.RoleRank(successor.Role) ||
+			(domain.RoleRank(participant.Role) == domain.RoleRank(successor.Role) && participant.JoinedAt.Before(successor.JoinedAt)) {
+			successor = &participants[idx]
+		}
+	}
+	return successor
+}
+
+// createSystemMessage stores a system message in the conversation and broadcasts it
+// to all participants and targets (so added/removed users are notified as well)
+// Failures are logged only, they shouldn't fail the membership change itself
+func (service *conversationServiceImpl) createSystemMessage(ctx context.Context, conv *domain.Conversation, payload *domain.SystemPayload, content string) {
+	// 1. Save system message
+	message := &domain.Message{
+		ConversationID: conv.ID,
+		SenderID: payload.Actor,
+		Content: content,
+		Type: domain.MessageTypeSystem,
+		Payload: payload,
+	}
+
+	if err := service.messageRepo.Create(ctx, message); err != nil {
+		log.Printf("Failed to create system message %s in conversation %s: %v", payload.Action, conv.ID, err)
+		return
+	}
+
+	if service.hub == nil {
+		return
+	}
+
+	// 2. Reload with sender info
+	savedMessage, err := service.messageRepo.FindByID(ctx, message.ID)
+	if err != nil {
+		log.Printf("Failed to reload system message %s: %v", message.ID, err)
+		return
+	}
+
+	// The same message goes to every recipient, contacts or not, so only "everyone" shows the actor's presence
+	service.loadPresence(ctx, &savedMessage.Sender)
+	if savedMessage.Sender.PresenceVisibility != domain.PresenceVisibilityEveryone {
+		savedMessage.Sender.HidePresence()
+	}
+
+	// 3. Broadcast as a regular new message
+	recipients := make(map[string]bool)
+	for _, participant := range conv.Participants {
+		recipients[participant.UserID] = true
+	}
+	for _, targetID := range payload.Targets {
+		recipients[targetID] = true
+	}
+
+	recipientIDs := make([]string, 0, len(recipients))
+	for recipientID := range recipients {
+		recipientIDs = append(recipientIDs, recipientID)
+	}
+
+	service.sendEvent(recipientIDs, conv.ID, websocket.EventNewMessage, savedMessage)
+}
+
+// notifyParticipantRemoved tells remaining participants that someone is gone,
+// and tells the removed user to drop the conversation from their list
+func (service *conversationServiceImpl) notifyParticipantRemoved(conv *domain.Conversation, removedUserID, actorID, reason string) {
+	data := websocket.ParticipantRemovedData{
+		UserID: removedUserID,
+		ActorID: actorID,
+		Reason: reason,
+	}
+
+	var remainingIDs []string
+	for _, participant := range conv.Participants {
+		if participant.UserID != removedUserID {
+			remainingIDs = append(remainingIDs, participant.UserID)
+		}
+	}
+
+	service.sendEvent(remainingIDs, conv.ID, websocket.EventParticipantRemoved, data)
+	service.sendEvent([]string{removedUserID}, conv.ID, websocket.EventRemovedFromConversation, data)
+}
+
+// sendConversationEvent sends the conversation detail to each user, built from their own point of view
+// (DM display name and avatar, and presence, depend on who is looking)
+func (service *conversationServiceImpl) sendConversationEvent(ctx context.Context, conv *domain.Conversation, userIDs []string, event string) {
+	contactSets := service.contactSetsAmong(ctx, append(participantIDs(conv), userIDs...))
+	for _, recipientID := range userIDs {
+		service.sendEvent([]string{recipientID}, conv.ID, event, service.buildConversationResponseFor(conv, recipientID, contactSets[recipientID]))
+	}
+}
+
+// sendEvent sends a WebSocket event to the given users (if they are online)
+func (service *conversationServiceImpl) sendEvent(userIDs []string, conversationID, event string, data interface{}) {
+	if service.hub == nil || len(userIDs) == 0 {
+		return
+	}
+
+	jsonData, err := json.Marshal(websocket.WSMessage{
+		Event: event,
+		ConversationID: conversationID,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s event: %v", event, err)
+		return
+	}
+
+	service.hub.SendToUsers(userIDs, jsonData)
+}
+
+// participantName returns the name of a participant (used in system message content)
+func participantName(conv *domain.Conversation, userID string) string {
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID {
+			return participant.User.Name
+		}
+	}
+	return "Someone"
+}
+
+// participantIDs returns the user IDs of all participants in a conversation
+func participantIDs(conv *domain.Conversation) []string {
+	ids := make([]string, 0, len(conv.Participants))
+	for _, participant := range conv.Participants {
+		ids = append(ids, participant.UserID)
+	}
+	return ids
+}
+
+// findContactIDs returns the accepted contacts of userID
+func (service *conversationServiceImpl) findContactIDs(ctx context.Context, userID string) (map[string]bool, error) {
+	ids, err := service.contactRepo.FindContactIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	contactIDs := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		contactIDs[id] = true
+	}
+	return contactIDs, nil
+}
+
+// contactSet is findContactIDs for presence: best effort, on failure "contacts only" users are shown as offline
+func (service *conversationServiceImpl) contactSet(ctx context.Context, userID string) map[string]bool {
+	contactIDs, err := service.findContactIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load contacts: %v", err)
+		return map[string]bool{}
+	}
+	return contactIDs
+}
+
+// contactSetsAmong is contactSet for several users at once, limited to contacts within the list (one query).
+// Best effort like contactSet: on failure everyone gets an empty set
+func (service *conversationServiceImpl) contactSetsAmong(ctx context.Context, userIDs []string) map[string]map[string]bool {
+	contactSets := make(map[string]map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		contactSets[id] = map[string]bool{}
+	}
+
+	contacts, err := service.contactRepo.FindAcceptedAmong(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to load contacts: %v", err)
+		return contactSets
+	}
+
+	for _, contact := range contacts {
+		contactSets[contact.RequesterID][contact.AddresseeID] = true
+		contactSets[contact.AddresseeID][contact.RequesterID] = true
+	}
+	return contactSets
+}
+
+// needsMessageRequest checks whether a new DM from userID goes to targetUserID's message request inbox
+// (the target only accepts DMs from contacts and userID isn't one)
+func (service *conversationServiceImpl) needsMessageRequest(ctx context.Context, userID, targetUserID string) (bool, error) {
+	target, err := service.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, exceptions.NewNotFoundError("User " + targetUserID + " not found")
+		}
+		return false, err
+	}
+
+	if !target.DMContactsOnly || targetUserID == userID {
+		return false, nil
+	}
+
+	isContact, err := service.contactRepo.AreContacts(ctx, userID, targetUserID)
+	if err != nil {
+		return false, err
+	}
+	return !isContact, nil
+}
+
+// findMessageRequest finds a conversation that is waiting in the user's message request inbox
+func (service *conversationServiceImpl) findMessageRequest(ctx context.Context, userID, conversationID string) (*domain.Conversation, error) {
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	for _, participant := range conv.Participants {
+		if participant.UserID != userID {
+			continue
+		}
+		if !participant.IsMessageRequest {
+			return nil, exceptions.NewBadRequestError("This conversation is not a message request")
+		}
+		return conv, nil
+	}
+
+	return nil, exceptions.NewForbiddenError("You are not a participant of this conversation")
+}
+
+// checkNotBlocked returns an error if userID and targetUserID have blocked each other
+func (service *conversationServiceImpl) checkNotBlocked(ctx context.Context, userID, targetUserID string) error {
+	blockedByMe, err := service.userBlockRepo.HasBlocked(ctx, userID, targetUserID)
+	if err != nil {
+		return err
+	}
+	if blockedByMe {
+		return exceptions.NewForbiddenError("You have blocked this user, unblock them first")
+	}
+
+	blockedMe, err := service.userBlockRepo.HasBlocked(ctx, targetUserID, userID)
+	if err != nil {
+		return err
+	}
+	if blockedMe {
+		return exceptions.NewForbiddenError("You can't start a conversation with this user")
+	}
+
+	return nil
+}
+
+// checkNotBanned returns a ForbiddenError if the user has an active ban in the conversation
+func (service *conversationServiceImpl) checkNotBanned(ctx context.Context, conversationID, userID string) error {
+	_, err := service.banRepo.FindActive(ctx, conversationID, userID)
+	if err == nil {
+		return exceptions.NewForbiddenError("User " + userID + " is banned from this conversation")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// findGroupWithPermission finds a group and checks the user's role grants the permission
+func (service *conversationServiceImpl) findGroupWithPermission(ctx context.Context, userID, conversationID, permission string) (*domain.Conversation, error) {
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	if conv.Type != "group" {
+		return nil, exceptions.NewBadRequestError("This action is only available for groups")
+	}
+
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID && domain.HasPermission(participant.Role, permission) {
+			return conv, nil
+		}
+	}
+
+	return nil, exceptions.NewForbiddenError("You don't have permission to perform this action")
+}
@@ -0,0 +1,72 @@
+package conversation
+
+import (
+	"chatapp-api/models/domain"
+	"testing"
+	"time"
+)
+
+func TestFindSuccessor(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	participant := func(userID, role string, joinedDays int) domain.Participant {
+		return domain.Participant{UserID: userID, Role: role, JoinedAt: base.AddDate(0, 0, joinedDays)}
+	}
+
+	tests := []struct {
+		name         string
+		participants []domain.Participant
+		leaving      string
+		want         string // Successor user ID, empty when nobody is left
+	}{
+		{
+			name:         "nobody left",
+			participants: []domain.Participant{participant("owner", domain.RoleOwner, 0)},
+			leaving:      "owner",
+			want:         "",
+		},
+		{
+			name: "highest role wins over seniority",
+			participants: []domain.Participant{
+				participant("owner", domain.RoleOwner, 0),
+				participant("member", domain.RoleMember, 1),
+				participant("moderator", domain.RoleModerator, 3),
+				participant("admin", domain.RoleAdmin, 5),
+			},
+			leaving: "owner",
+			want:    "admin",
+		},
+		{
+			name: "longest member on equal roles",
+			participants: []domain.Participant{
+				participant("owner", domain.RoleOwner, 0),
+				participant("late", domain.RoleAdmin, 9),
+				participant("early", domain.RoleAdmin, 2),
+			},
+			leaving: "owner",
+			want:    "early",
+		},
+		{
+			name: "leaving participant is never picked",
+			participants: []domain.Participant{
+				participant("admin", domain.RoleAdmin, 0),
+				participant("member", domain.RoleMember, 1),
+			},
+			leaving: "admin",
+			want:    "member",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			successor := findSuccessor(tt.participants, tt.leaving)
+
+			got := ""
+			if successor != nil {
+				got = successor.UserID
+			}
+			if got != tt.want {
+				t.Errorf("findSuccessor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package invite
+
+import (
+	"chatapp-api/models/web"
+	"context"
+)
+
+// InviteService interface for group invite links and join requests
+type InviteService interface {
+	// CreateInvite creates a shareable invite link for a group (owner/admin only)
+	CreateInvite(ctx context.Context, userID, conversationID string, req *web.CreateInviteRequest) (*web.InviteResponse, error)
+
+	// GetInvites lists all invite links of a group (owner/admin only)
+	GetInvites(ctx context.Context, userID, conversationID string) ([]web.InviteResponse, error)
+
+	// RevokeInvite revokes an invite link so it can't be used anymore (owner/admin only)
+	RevokeInvite(ctx context.Context, userID, conversationID, inviteID string) error
+
+	// JoinByCode joins a group through an invite code, or creates a join request if approval is required
+	JoinByCode(ctx context.Context, userID, code string) (*web.JoinResult, error)
+
+	// GetJoinRequests lists pending join requests of a group (owner/admin only)
+	GetJoinRequests(ctx context.Context, userID, conversationID string) ([]web.JoinRequestResponse, error)
+
+	// ReviewJoinRequest approves or rejects a pending join request (owner/admin only)
+	ReviewJoinRequest(ctx context.Context, userID, conversationID, requestID string, approve bool) (*web.JoinRequestResponse, error)
+}
@@ -0,0 +1,409 @@
+package invite
+
+import (
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"chatapp-api/models/web"
+	banRepo "chatapp-api/repositories/ban"
+	convRepo "chatapp-api/repositories/conversation"
+	inviteRepo "chatapp-api/repositories/invite"
+	joinRequestRepo "chatapp-api/repositories/join_request"
+	presenceRepo "chatapp-api/repositories/presence"
+	convService "chatapp-api/services/conversation"
+	"chatapp-api/utils"
+	"chatapp-api/websocket"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// inviteCodeLength is the length of generated invite codes
+const inviteCodeLength = 10
+
+// Join result statuses
+const (
+	JoinStatusJoined  = "joined"
+	JoinStatusPending = "pending"
+)
+
+// inviteServiceImpl implements InviteService interface
+type inviteServiceImpl struct {
+	inviteRepo      inviteRepo.InviteRepository
+	joinRequestRepo joinRequestRepo.JoinRequestRepository
+	convRepo        convRepo.ConversationRepository
+	banRepo         banRepo.BanRepository
+	presenceRepo    presenceRepo.PresenceRepository
+	convService     convService.ConversationService
+	hub             *websocket.Hub
+}
+
+// NewInviteService creates a new InviteService instance
+func NewInviteService(
+	inviteRepo inviteRepo.InviteRepository,
+	joinRequestRepo joinRequestRepo.JoinRequestRepository,
+	convRepo convRepo.ConversationRepository,
+	banRepo banRepo.BanRepository,
+	presenceRepo presenceRepo.PresenceRepository,
+	convService convService.ConversationService,
+	hub *websocket.Hub) InviteService {
+	return &inviteServiceImpl{
+		inviteRepo:      inviteRepo,
+		joinRequestRepo: joinRequestRepo,
+		convRepo:        convRepo,
+		banRepo:         banRepo,
+		presenceRepo:    presenceRepo,
+		convService:     convService,
+		hub:             hub,
+	}
+}
+
+// CreateInvite implements InviteService
+func (service *inviteServiceImpl) CreateInvite(ctx context.Context, userID, conversationID string, req *web.CreateInviteRequest) (*web.InviteResponse, error) {
+	// 1. Validation: User must be allowed to manage invites of this group
+	if _, err := service.findGroupForManager(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	// 2. Build invite
+	invite := &domain.ConversationInvite{
+		ConversationID: conversationID,
+		Code: utils.GenerateCode(inviteCodeLength),
+		CreatedBy: userID,
+		MaxUses: req.MaxUses,
+		RequireApproval: req.RequireApproval,
+	}
+
+	if req.ExpiresInHours != nil {
+		expiresAt := time.Now().Add(time.Duration(*req.ExpiresInHours) * time.Hour)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	// 3. Save to database
+	if err := service.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	response := buildInviteResponse(invite)
+	return &response, nil
+}
+
+// GetInvites implements InviteService
+func (service *inviteServiceImpl) GetInvites(ctx context.Context, userID, conversationID string) ([]web.InviteResponse, error) {
+	// 1. Validation: User must be allowed to manage invites of this group
+	if _, err := service.findGroupForManager(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	// 2. Get all invites
+	invites, err := service.inviteRepo.FindByConversationID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Convert to response
+	result := make([]web.InviteResponse, len(invites))
+	for idx := range invites {
+		result[idx] = buildInviteResponse(&invites[idx])
+	}
+
+	return result, nil
+}
+
+// RevokeInvite implements InviteService
+func (service *inviteServiceImpl) RevokeInvite(ctx context.Context, userID, conversationID, inviteID string) error {
+	// 1. Validation: User must be allowed to manage invites of this group
+	if _, err := service.findGroupForManager(ctx, userID, conversationID); err != nil {
+		return err
+	}
+
+	// 2. Find invite and check it belongs to this group
+	invite, err := service.inviteRepo.FindByID(ctx, inviteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("Invite not found")
+		}
+		return err
+	}
+
+	if invite.ConversationID != conversationID {
+		return exceptions.NewNotFoundError("Invite not found")
+	}
+
+	// 3. Revoke
+	return service.inviteRepo.Revoke(ctx, invite.ID)
+}
+
+// JoinByCode implements InviteService
+func (service *inviteServiceImpl) JoinByCode(ctx context.Context, userID, code string) (*web.JoinResult, error) {
+	// 1. Find invite by code
+	invite, err := service.inviteRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Invite not found")
+		}
+		return nil, err
+	}
+
+	// 2. Validation: Invite must be active (not revoked, expired or used up)
+	if !invite.IsUsable(time.Now()) {
+		return nil, exceptions.NewBadRequestError("Invite link is no longer valid")
+	}
+
+	// 3. Validation: User must not be a participant yet
+	conv, err := service.convRepo.FindByID(ctx, invite.ConversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID {
+			return nil, exceptions.NewConflictError("You are already a participant of this conversation")
+		}
+	}
+
+	// Validation: Banned users can't join or request to join
+	if _, err := service.banRepo.FindActive(ctx, conv.ID, userID); err == nil {
+		return nil, exceptions.NewForbiddenError("You are banned from this conversation")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 4. Approval mode: one pending request per user, uses are counted per request
+	if invite.RequireApproval {
+		if existing, err := service.joinRequestRepo.FindPending(ctx, conv.ID, userID); err == nil {
+			service.loadPresence(ctx, &existing.User)
+			response := buildJoinRequestResponse(existing)
+			return &web.JoinResult{Status: JoinStatusPending, JoinRequest: &response}, nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		// The use is only taken if the request is recorded
+		request := &domain.JoinRequest{
+			ConversationID: conv.ID,
+			InviteID: invite.ID,
+			UserID: userID,
+			Status: domain.JoinRequestPending,
+		}
+		used, err := service.inviteRepo.UseForJoinRequest(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if !used {
+			return nil, exceptions.NewBadRequestError("Invite link is no longer valid")
+		}
+
+		// Reload with user info and let the admins know
+		savedRequest, err := service.joinRequestRepo.FindByID(ctx, request.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		service.loadPresence(ctx, &savedRequest.User)
+		response := buildJoinRequestResponse(savedRequest)
+		service.notifyManagers(conv, websocket.EventJoinRequestCreated, response)
+
+		return &web.JoinResult{Status: JoinStatusPending, JoinRequest: &response}, nil
+	}
+
+	// 5. Direct join
+	if err := service.useInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	if err := service.convService.AddMember(ctx, userID, conv.ID, userID); err != nil {
+		// The user didn't join: give the use back
+		if releaseErr := service.inviteRepo.DecrementUseCount(ctx, invite.ID); releaseErr != nil {
+			log.Printf("Failed to give back a use of invite %s: %v", invite.ID, releaseErr)
+		}
+		return nil, err
+	}
+
+	joinedConv, err := service.convService.GetConversationByID(ctx, userID, conv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &web.JoinResult{Status: JoinStatusJoined, Conversation: joinedConv}, nil
+}
+
+// GetJoinRequests implements InviteService
+func (service *inviteServiceImpl) GetJoinRequests(ctx context.Context, userID, conversationID string) ([]web.JoinRequestResponse, error) {
+	// 1. Validation: User must be allowed to manage invites of this group
+	if _, err := service.findGroupForManager(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	// 2. Get pending requests
+	requests, err := service.joinRequestRepo.FindPendingByConversationID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Load live presence and convert to response
+	users := make([]*domain.User, len(requests))
+	for idx := range requests {
+		users[idx] = &requests[idx].User
+	}
+	service.loadPresence(ctx, users...)
+
+	result := make([]web.JoinRequestResponse, len(requests))
+	for idx := range requests {
+		result[idx] = buildJoinRequestResponse(&requests[idx])
+	}
+
+	return result, nil
+}
+
+// ReviewJoinRequest implements InviteService
+func (service *inviteServiceImpl) ReviewJoinRequest(ctx context.Context, userID, conversationID, requestID string, approve bool) (*web.JoinRequestResponse, error) {
+	// 1. Validation: User must be allowed to manage invites of this group
+	if _, err := service.findGroupForManager(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	// 2. Find request and check it belongs to this group
+	request, err := service.joinRequestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Join request not found")
+		}
+		return nil, err
+	}
+
+	if request.ConversationID != conversationID {
+		return nil, exceptions.NewNotFoundError("Join request not found")
+	}
+
+	// 3. Validation: Only pending requests can be reviewed
+	if request.Status != domain.JoinRequestPending {
+		return nil, exceptions.NewConflictError("Join request has already been " + request.Status)
+	}
+
+	// 4. Approve: add the user to the group first, so a failure keeps the request pending
+	if approve {
+		if err := service.convService.AddMember(ctx, userID, conversationID, request.UserID); err != nil {
+			return nil, err
+		}
+		request.Status = domain.JoinRequestApproved
+	} else {
+		request.Status = domain.JoinRequestRejected
+	}
+
+	// 5. Save review
+	now := time.Now()
+	request.ReviewedBy = &userID
+	request.ReviewedAt = &now
+
+	if err := service.joinRequestRepo.Update(ctx, request); err != nil {
+		return nil, err
+	}
+
+	service.loadPresence(ctx, &request.User)
+	response := buildJoinRequestResponse(request)
+	return &response, nil
+}
+
+// findGroupForManager finds a group and checks the user can manage its invites
+func (service *inviteServiceImpl) findGroupForManager(ctx context.Context, userID, conversationID string) (*domain.Conversation, error) {
+	conv, err := service.convRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Conversation not found")
+		}
+		return nil, err
+	}
+
+	if conv.Type != "group" {
+		return nil, exceptions.NewBadRequestError("Invites are only available for groups")
+	}
+
+	for _, participant := range conv.Participants {
+		if participant.UserID == userID && domain.HasPermission(participant.Role, domain.PermissionManageInvites) {
+			return conv, nil
+		}
+	}
+
+	return nil, exceptions.NewForbiddenError("Only owner or admin can manage invites")
+}
+
+// useInvite counts one use of the invite, failing if the last use was just taken
+func (service *inviteServiceImpl) useInvite(ctx context.Context, invite *domain.ConversationInvite) error {
+	used, err := service.inviteRepo.IncrementUseCount(ctx, invite.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return exceptions.NewBadRequestError("Invite link is no longer valid")
+	}
+	return nil
+}
+
+// notifyManagers sends a WebSocket event to everyone who can manage invites of the group
+func (service *inviteServiceImpl) notifyManagers(conv *domain.Conversation, event string, data interface{}) {
+	if service.hub == nil {
+		return
+	}
+
+	jsonData, err := json.Marshal(websocket.WSMessage{
+		Event: event,
+		ConversationID: conv.ID,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s event: %v", event, err)
+		return
+	}
+
+	for _, participant := range conv.Participants {
+		if domain.HasPermission(participant.Role, domain.PermissionManageInvites) {
+			service.hub.SendToUser(participant.UserID, jsonData)
+		}
+	}
+}
+
+// loadPresence fills users' live presence from Redis
+// Presence is best effort: on failure users are shown as offline
+func (service *inviteServiceImpl) loadPresence(ctx context.Context, users ...*domain.User) {
+	if err := service.presenceRepo.Load(ctx, users...); err != nil {
+		log.Printf("Failed to load presence: %v", err)
+	}
+}
+
+// buildInviteResponse converts domain.ConversationInvite to web.InviteResponse
+func buildInviteResponse(invite *domain.ConversationInvite) web.InviteResponse {
+	return web.InviteResponse{
+		ID: invite.ID,
+		ConversationID: invite.ConversationID,
+		Code: invite.Code,
+		CreatedBy: invite.CreatedBy,
+		ExpiresAt: invite.ExpiresAt,
+		MaxUses: invite.MaxUses,
+		UseCount: invite.UseCount,
+		RequireApproval: invite.RequireApproval,
+		IsActive: invite.IsUsable(time.Now()),
+		RevokedAt: invite.RevokedAt,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+// buildJoinRequestResponse converts domain.JoinRequest to web.JoinRequestResponse
+// The requester isn't in the group yet, so presence is only shown if they share it with everyone
+func buildJoinRequestResponse(request *domain.JoinRequest) web.JoinRequestResponse {
+	return web.JoinRequestResponse{
+		ID: request.ID,
+		ConversationID: request.ConversationID,
+		User: web.NewUserBrief(&request.User, "", false),
+		Status: request.Status,
+		ReviewedBy: request.ReviewedBy,
+		ReviewedAt: request.ReviewedAt,
+		CreatedAt: request.CreatedAt,
+	}
+}
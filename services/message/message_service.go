@@ -21,9 +21,12 @@ type MessageService interface {
 	// UpdateMessage updates a message
 	UpdateMessage(ctx context.Context, userID, messageID string, req *web.UpdateMessageRequest) (*domain.Message, error)
 
-	// DeleteMessage deletes a message
-	DeleteMessage(ctx context.Context, userID, messageID string) error
+	// DeleteMessage deletes a message for the caller only ("me") or for every participant ("everyone")
+	DeleteMessage(ctx context.Context, userID, messageID string, req *web.DeleteMessageRequest) error
 
 	// GetMessageReceipts returns all receipts for a message (who read, delivered, etc.)
 	GetMessageReceipts(ctx context.Context, userID, messageID string) ([]domain.MessageReceipt, error)
+
+	// GetMentions lists where the user was mentioned (newest first, with the message)
+	GetMentions(ctx context.Context, userID string, page, limit int) ([]domain.MessageMention, *web.PaginationMeta, error)
 }
\ No newline at end of file
@@ -4,24 +4,51 @@ import (
 	"chatapp-api/exceptions"
 	"chatapp-api/models/domain"
 	"chatapp-api/models/web"
+	attachmentRepo "chatapp-api/repositories/attachment"
+	contactRepo "chatapp-api/repositories/contact"
 	conversationRepo "chatapp-api/repositories/conversation"
 	messageRepo "chatapp-api/repositories/message"
+	mentionRepo "chatapp-api/repositories/message_mention"
 	receiptRepo "chatapp-api/repositories/message_receipt"
+	moderationLogRepo "chatapp-api/repositories/moderation_log"
+	presenceRepo "chatapp-api/repositories/presence"
+	userBlockRepo "chatapp-api/repositories/user_block"
 	"chatapp-api/websocket"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"math"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Scopes for deleting a message
+const (
+	DeleteScopeMe       = "me"
+	DeleteScopeEveryone = "everyone"
+)
+
+// deleteForEveryoneWindow is how long after sending a message can still be deleted for everyone
+const deleteForEveryoneWindow = 48 * time.Hour
+
+// deletedMessageContent replaces the content of a message deleted for everyone
+const deletedMessageContent = "This message was deleted"
+
 // messageServiceImpl implements MessageService interface
 type messageServiceImpl struct {
 	messageRepo        messageRepo.MessageRepository
 	conversationRepo   conversationRepo.ConversationRepository
 	receiptRepo        receiptRepo.MessageReceiptRepository
+	mentionRepo        mentionRepo.MessageMentionRepository
+	attachmentRepo     attachmentRepo.AttachmentRepository
+	moderationLogRepo  moderationLogRepo.ModerationLogRepository
+	presenceRepo       presenceRepo.PresenceRepository
+	userBlockRepo      userBlockRepo.UserBlockRepository
+	contactRepo        contactRepo.ContactRepository
 	hub                *websocket.Hub
 }
 
@@ -30,11 +57,23 @@ func NewMessageService(
 	messageRepo messageRepo.MessageRepository, 
 	conversationRepo conversationRepo.ConversationRepository, 
 	receiptRepo receiptRepo.MessageReceiptRepository, 
+	mentionRepo mentionRepo.MessageMentionRepository,
+	attachmentRepo attachmentRepo.AttachmentRepository,
+	moderationLogRepo moderationLogRepo.ModerationLogRepository,
+	presenceRepo presenceRepo.PresenceRepository,
+	userBlockRepo userBlockRepo.UserBlockRepository,
+	contactRepo contactRepo.ContactRepository,
 	hub *websocket.Hub) MessageService {
 	return &messageServiceImpl{
 		messageRepo: messageRepo, 
 		conversationRepo: conversationRepo,
 		receiptRepo: receiptRepo,
+		mentionRepo: mentionRepo,
+		attachmentRepo: attachmentRepo,
+		moderationLogRepo: moderationLogRepo,
+		presenceRepo: presenceRepo,
+		userBlockRepo: userBlockRepo,
+		contactRepo: contactRepo,
 		hub: hub,
 	}
 }
@@ -51,18 +90,39 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 	}
 
 	// 2. Validate: Check if sender is a participant
-	isParticipant := false
-	for _, participant := range conv.Participants {
+	var senderParticipant *domain.Participant
+	for idx, participant := range conv.Participants {
 		if participant.UserID == senderID {
-			isParticipant = true
+			senderParticipant = &conv.Participants[idx]
 			break
 		}
 	}
 
-	if !isParticipant {
+	if senderParticipant == nil {
 		return nil, exceptions.NewForbiddenError("You are not a participant in this conversation")
 	}
 
+	// A DM in the sender's message request inbox must be accepted before replying
+	if senderParticipant.IsMessageRequest {
+		return nil, exceptions.NewForbiddenError("Accept the message request before replying")
+	}
+
+	// In a DM, nobody can send if either side has blocked the other
+	if conv.Type == "direct" {
+		for _, participant := range conv.Participants {
+			if participant.UserID == senderID {
+				continue
+			}
+			blocked, err := service.userBlockRepo.IsBlockedEitherWay(ctx, senderID, participant.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if blocked {
+				return nil, exceptions.NewForbiddenError("You can't send messages in this conversation")
+			}
+		}
+	}
+
 	// 3. Validate message type
 	validTypes := map[string]bool{
 		"text":  true,
@@ -81,10 +141,16 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 		return nil, exceptions.NewBadRequestError("Invalid message type. Allowed: text, image, file, video, audio")
 	}
 
-	// 4. Validate content based on type
-	if messageType == "text" && req.Content == "" {
+	// 4. Validate content based on type (a message with attachments needs no text)
+	if messageType == "text" && req.Content == "" && len(req.AttachmentIDs) == 0 {
 		return nil, exceptions.NewBadRequestError("Message content is required for text type")
 	}
+
+	// Attachments must be the sender's own uploads, not sent yet
+	attachmentIDs, err := service.validateAttachments(ctx, senderID, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
 	
 	// 5. Create message
 	message := &domain.Message{
@@ -95,11 +161,27 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 		Type: messageType,
 	}
 
+	// Resolve @mentions (only participants can be mentioned)
+	mentions := service.resolveMentions(ctx, conv, senderID, mentionText(message))
+	message.Mentions = mentions.entities
+
 	// 6. Save to database
 	if err := service.messageRepo.Create(ctx, message); err != nil {
 		return nil, err
 	}
 
+	// Link the attachments to the message
+	if len(attachmentIDs) > 0 {
+		if err := service.attachmentRepo.AttachToMessage(ctx, attachmentIDs, message.ID, senderID); err != nil {
+			// Sent with another message in the meantime: don't leave a message without its files
+			service.messageRepo.Delete(ctx, message.ID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, exceptions.NewConflictError("An attachment was already sent with another message")
+			}
+			return nil, err
+		}
+	}
+
 	// 7. Create receipts for all recipients (everyone except sender)
 	// Each recipient gets a receipt with initial status "sent"
 	var receipts []*domain.MessageReceipt
@@ -123,6 +205,9 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 		}
 	}
 
+	// Save mentioned users (for GET /mentions), failure shouldn't block delivery either
+	service.saveMentions(ctx, message, mentions.users)
+
 	// 8. Reload message with sender info
 	// Retrieved the saved message complete with sender data
 	savedMessage, err := service.messageRepo.FindByID(ctx, message.ID)
@@ -130,6 +215,12 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 		return nil, err
 	}
 
+	// The same message goes to every participant, contacts or not, so only "everyone" shows the sender's presence
+	service.loadSenderPresence(ctx, savedMessage)
+	if savedMessage.Sender.PresenceVisibility != domain.PresenceVisibilityEveryone {
+		savedMessage.Sender.HidePresence()
+	}
+
 	// 8. Broadcast new message to all participants via WebSocket
 	// Send realtime notification to all online participants
 	if service.hub != nil {
@@ -144,8 +235,13 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 		jsonData, err := json.Marshal(wsMessage)
 		if err == nil {
 			// 8c. Collcet all participants for this conversation
+			// (a DM still in the recipient's message request inbox doesn't notify them)
 			var participantIDs []string
 			for _, participant := range conv.Participants {
+				if participant.IsMessageRequest {
+					delete(mentions.users, participant.UserID)
+					continue
+				}
 				participantIDs = append(participantIDs, participant.UserID)
 			}
 
@@ -154,6 +250,9 @@ func (service *messageServiceImpl) SendMessage(ctx context.Context, senderID str
 			log.Printf("Broadcasted new message to %d participants", len(participantIDs))
 		}
 	}
+
+	// 9. Notify mentioned users
+	service.notifyMentions(savedMessage, mentions.users)
 	
 	return savedMessage, nil
 }
@@ -188,7 +287,7 @@ func (service *messageServiceImpl) GetMessages(ctx context.Context, userID, conv
 	}
 
 	// 4. Fetch messages (limit + 1 to check if has more)
-	messages, err := service.messageRepo.FindByConversationIDWithCursor(ctx, conversationID, cursor, limit+1)
+	messages, err := service.messageRepo.FindByConversationIDWithCursor(ctx, conversationID, userID, cursor, limit+1)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -209,6 +308,14 @@ func (service *messageServiceImpl) GetMessages(ctx context.Context, userID, conv
 		NextCursor: nextCursor,
 	}
 
+	// 6. Load senders' live presence and apply their presence privacy
+	messagePtrs := make([]*domain.Message, len(messages))
+	for idx := range messages {
+		messagePtrs[idx] = &messages[idx]
+	}
+	service.loadSenderPresence(ctx, messagePtrs...)
+	service.hideSenderPresence(ctx, userID, messagePtrs...)
+
 	return messages, cursorMeta, nil
 }
 
@@ -241,7 +348,18 @@ func (service *messageServiceImpl) GetMessageByID(ctx context.Context, userID, m
 		return nil, exceptions.NewForbiddenError("You are not a participant in this conversation")
 	}
 
-	// 3. Return the message
+	// 3. Validate: a message deleted for the user ("delete for me") is gone for them
+	hidden, err := service.messageRepo.IsHiddenForUser(ctx, message.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if hidden {
+		return nil, exceptions.NewNotFoundError("Message not found")
+	}
+
+	// 4. Return the message
+	service.loadSenderPresence(ctx, message)
+	service.hideSenderPresence(ctx, userID, message)
 	return message, nil
 }
 
@@ -266,6 +384,14 @@ func (service *messageServiceImpl) UpdateMessage(ctx context.Context, userID, me
 		return nil, exceptions.NewForbiddenError("You can only edit your own message")
 	}
 
+	// Validate: Deleted and system messages can't be edited
+	if message.IsDeleted {
+		return nil, exceptions.NewBadRequestError("Cannot edit a deleted message")
+	}
+	if message.Type == domain.MessageTypeSystem {
+		return nil, exceptions.NewBadRequestError("Cannot edit a system message")
+	}
+
 	// 4. Update based on message type
 	if message.Type == "text" {
 		// Text messages: can update content
@@ -284,17 +410,37 @@ func (service *messageServiceImpl) UpdateMessage(ctx context.Context, userID, me
 		}
 	}
 
+	// Re-resolve mentions of the edited text
+	conv, err := service.conversationRepo.FindByID(ctx, message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions := service.resolveMentions(ctx, conv, userID, mentionText(message))
+	message.Mentions = mentions.entities
+
 	// 5. Save to database
 	if err := service.messageRepo.Update(ctx, message); err != nil {
 		return nil, err
 	}
 
+	// Only users who weren't mentioned before get a notification
+	newMentions := service.updateMentions(ctx, message, mentions.users)
+
 	// 6. Reload to get fresh data
-	return service.messageRepo.FindByID(ctx, message.ID)
+	updatedMessage, err := service.messageRepo.FindByID(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	service.loadSenderPresence(ctx, updatedMessage)
+	service.hideSenderPresence(ctx, userID, updatedMessage)
+	service.notifyMentions(updatedMessage, newMentions)
+	return updatedMessage, nil
 }
 
 // DeleteMessage implements MessageService
-func (service *messageServiceImpl) DeleteMessage(ctx context.Context, userID, messageID string) error {
+func (service *messageServiceImpl) DeleteMessage(ctx context.Context, userID, messageID string, req *web.DeleteMessageRequest) error {
 	// 1. Find the message
 	message, err := service.messageRepo.FindByID(ctx, messageID)
 	if err != nil {
@@ -304,13 +450,107 @@ func (service *messageServiceImpl) DeleteMessage(ctx context.Context, userID, me
 		return err
 	}
 
-	// 2. Validate: Only sender can delete their own message
-	if message.SenderID != userID {
-		return exceptions.NewForbiddenError("You can only delete your own message")
+	// 2. Validate: Check if user is a participant in this conversation
+	conv, err := service.conversationRepo.FindByID(ctx, message.ConversationID)
+	if err != nil {
+		return err
 	}
 
-	// 3. Delete the message
-	return service.messageRepo.Delete(ctx, message.ID)
+	var userParticipant *domain.Participant
+	for idx, participant := range conv.Participants {
+		if participant.UserID == userID {
+			userParticipant = &conv.Participants[idx]
+			break
+		}
+	}
+
+	if userParticipant == nil {
+		return exceptions.NewForbiddenError("You are not a participant in this conversation")
+	}
+
+	// 3. Delete for me: any participant can hide a message for themselves
+	scope := req.Scope
+	if scope == "" {
+		scope = DeleteScopeEveryone
+	}
+
+	if scope == DeleteScopeMe {
+		return service.messageRepo.HideForUser(ctx, message.ID, userID)
+	}
+
+	// 4. Delete for everyone: only the sender or a group moderator (owner/admin/moderator)
+	// Deleting someone else's message is a moderation action
+	isModeration := message.SenderID != userID
+	if isModeration {
+		canModerate := conv.Type == "group" && domain.HasPermission(userParticipant.Role, domain.PermissionDeleteMessages)
+		if !canModerate {
+			return exceptions.NewForbiddenError("You can only delete your own message for everyone")
+		}
+
+		// Cannot moderate a participant with an equal or higher role
+		for _, participant := range conv.Participants {
+			if participant.UserID == message.SenderID && domain.RoleRank(participant.Role) >= domain.RoleRank(userParticipant.Role) {
+				return exceptions.NewForbiddenError("Cannot delete messages of a participant with an equal or higher role")
+			}
+		}
+	}
+
+	if message.IsDeleted {
+		return exceptions.NewBadRequestError("Message is already deleted")
+	}
+
+	if message.Type == domain.MessageTypeSystem {
+		return exceptions.NewBadRequestError("System messages can only be deleted for yourself")
+	}
+
+	// Senders are limited by the time window, moderators can remove spam at any time
+	if !isModeration && time.Since(message.CreatedAt) > deleteForEveryoneWindow {
+		return exceptions.NewBadRequestError(
+			fmt.Sprintf("Messages can only be deleted for everyone within %d hours of sending", int(deleteForEveryoneWindow.Hours())))
+	}
+
+	// 5. Replace the message with a tombstone (keeps its place in history)
+	// Its attachments are unlinked, so nobody but the uploader can download them anymore
+	if err := service.attachmentRepo.DetachFromMessage(ctx, message.ID); err != nil {
+		return err
+	}
+
+	// A tombstone is plain text, clients must not render it as an image or file
+	message.Content = deletedMessageContent
+	message.Type = "text"
+	message.Caption = nil
+	message.Mentions = nil
+	message.Attachments = nil
+	message.IsDeleted = true
+	message.DeletedBy = &userID
+
+	if err := service.messageRepo.Update(ctx, message); err != nil {
+		return err
+	}
+
+	if err := service.mentionRepo.DeleteByMessageID(ctx, message.ID); err != nil {
+		return err
+	}
+
+	// 6. Record moderation action in the conversation's moderation log
+	if isModeration {
+		moderationLog := &domain.ModerationLog{
+			ConversationID: conv.ID,
+			ActorID: userID,
+			TargetUserID: message.SenderID,
+			MessageID: &message.ID,
+			Action: domain.ModerationActionDeleteMessage,
+			Reason: req.Reason,
+		}
+		if err := service.moderationLogRepo.Create(ctx, moderationLog); err != nil {
+			return err
+		}
+	}
+
+	// 7. Notify all participants so clients can replace the bubble
+	service.broadcastToConversation(conv, websocket.EventMessageDeleted, message)
+
+	return nil
 }
 
 // GetMessageReceipts implements MessageService
@@ -339,3 +579,330 @@ func (service *messageServiceImpl) GetMessageReceipts(ctx context.Context, userI
 	return receipts, nil
 }
 
+// GetMentions implements MessageService
+func (service *messageServiceImpl) GetMentions(ctx context.Context, userID string, page, limit int) ([]domain.MessageMention, *web.PaginationMeta, error) {
+	// 1. Set default pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	// 2. Fetch mentions (with message and sender)
+	mentions, total, err := service.mentionRepo.FindByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 3. Load senders' live presence and apply their presence privacy
+	messagePtrs := make([]*domain.Message, len(mentions))
+	for idx := range mentions {
+		messagePtrs[idx] = &mentions[idx].Message
+	}
+	service.loadSenderPresence(ctx, messagePtrs...)
+	service.hideSenderPresence(ctx, userID, messagePtrs...)
+
+	// 4. Build pagination metadata
+	pagination := &web.PaginationMeta{
+		CurrentPage: page,
+		PerPage: limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+
+	return mentions, pagination, nil
+}
+
+// resolvedMentions is the result of resolving the @tokens of a message
+type resolvedMentions struct {
+	entities domain.MentionEntities
+	users    map[string]string // Mentioned user ID -> mention type ("user" wins over "all"/"here")
+}
+
+// validateAttachments checks that the attachments exist, were uploaded by the sender and weren't sent yet
+// Returns the IDs without duplicates, in the requested order
+func (service *messageServiceImpl) validateAttachments(ctx context.Context, senderID string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// 1. Remove duplicates, keeping the order
+	seen := make(map[string]bool, len(ids))
+	var attachmentIDs []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			attachmentIDs = append(attachmentIDs, id)
+		}
+	}
+
+	// 2. Load and check each attachment
+	attachments, err := service.attachmentRepo.FindByIDs(ctx, attachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*domain.Attachment, len(attachments))
+	for idx := range attachments {
+		found[attachments[idx].ID] = &attachments[idx]
+	}
+
+	for _, id := range attachmentIDs {
+		attachment, ok := found[id]
+		if !ok || attachment.UploaderID != senderID {
+			return nil, exceptions.NewBadRequestError(fmt.Sprintf("Attachment %s not found", id))
+		}
+		if attachment.MessageID != nil {
+			return nil, exceptions.NewConflictError(fmt.Sprintf("Attachment %s was already sent", id))
+		}
+	}
+
+	return attachmentIDs, nil
+}
+
+// mentionText returns the text of a message that can contain mentions (caption for media messages)
+func mentionText(message *domain.Message) string {
+	if message.Type == "text" {
+		return message.Content
+	}
+	if message.Caption != nil {
+		return *message.Caption
+	}
+	return ""
+}
+
+// resolveMentions resolves @username, @user_<id>, @all and @here in text to participants of the conversation
+// Tokens that don't match a participant aren't mentions. The sender and users blocked either way
+// with the sender get an entity but no mention
+func (service *messageServiceImpl) resolveMentions(ctx context.Context, conv *domain.Conversation, senderID, text string) resolvedMentions {
+	result := resolvedMentions{users: make(map[string]string)}
+
+	tokens := domain.ParseMentionTokens(text)
+	if len(tokens) == 0 {
+		return result
+	}
+
+	// 1. Index participants by ID and username
+	participantIDs := make([]string, 0, len(conv.Participants))
+	byUsername := make(map[string]string)
+	for _, participant := range conv.Participants {
+		participantIDs = append(participantIDs, participant.UserID)
+		if participant.User.Username != nil {
+			byUsername[strings.ToLower(*participant.User.Username)] = participant.UserID
+		}
+	}
+
+	// 2. Resolve each token
+	var statuses map[string]string
+	addUser := func(userID, mentionType string) {
+		if userID == senderID {
+			return
+		}
+		if current, ok := result.users[userID]; !ok || mentionType == domain.MentionTypeUser && current != domain.MentionTypeUser {
+			result.users[userID] = mentionType
+		}
+	}
+
+	for _, token := range tokens {
+		entity := domain.MentionEntity{Type: token.MentionKind(), Offset: token.Offset, Length: token.Length}
+
+		switch entity.Type {
+		case domain.MentionTypeAll:
+			for _, participantID := range participantIDs {
+				addUser(participantID, domain.MentionTypeAll)
+			}
+		case domain.MentionTypeHere:
+			// Live statuses are loaded once, on the first @here
+			if statuses == nil {
+				loaded, err := service.presenceRepo.GetStatuses(ctx, participantIDs)
+				if err != nil {
+					log.Printf("Failed to load presence for @here: %v", err)
+					loaded = make(map[string]string)
+				}
+				statuses = loaded
+			}
+			for _, participantID := range participantIDs {
+				if status, ok := statuses[participantID]; ok && status != domain.PresenceOffline {
+					addUser(participantID, domain.MentionTypeHere)
+				}
+			}
+		default:
+			userID, ok := byUsername[strings.ToLower(token.Handle)]
+			if !ok {
+				for _, participantID := range participantIDs {
+					if participantID == token.Handle {
+						userID, ok = participantID, true
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			entity.UserID = &userID
+			addUser(userID, domain.MentionTypeUser)
+		}
+
+		result.entities = append(result.entities, entity)
+	}
+
+	// 3. No mentions between blocked users
+	if len(result.users) > 0 {
+		relatedIDs, err := service.userBlockRepo.FindRelatedUserIDs(ctx, senderID)
+		if err != nil {
+			log.Printf("Failed to load blocked users for mentions: %v", err)
+		}
+		for _, relatedID := range relatedIDs {
+			delete(result.users, relatedID)
+		}
+	}
+
+	return result
+}
+
+// saveMentions stores the users mentioned in a message
+func (service *messageServiceImpl) saveMentions(ctx context.Context, message *domain.Message, users map[string]string) {
+	if len(users) == 0 {
+		return
+	}
+
+	mentions := make([]*domain.MessageMention, 0, len(users))
+	for userID, mentionType := range users {
+		mentions = append(mentions, &domain.MessageMention{
+			MessageID: message.ID,
+			ConversationID: message.ConversationID,
+			UserID: userID,
+			Type: mentionType,
+		})
+	}
+
+	if err := service.mentionRepo.CreateBatch(ctx, mentions); err != nil {
+		log.Printf("Failed to create message mentions %s: %v", message.ID, err)
+	}
+}
+
+// updateMentions replaces the mentioned users of an edited message
+// Returns the users who weren't mentioned before
+func (service *messageServiceImpl) updateMentions(ctx context.Context, message *domain.Message, users map[string]string) map[string]string {
+	previousIDs, err := service.mentionRepo.FindUserIDsByMessageID(ctx, message.ID)
+	if err != nil {
+		log.Printf("Failed to load message mentions %s: %v", message.ID, err)
+		return nil
+	}
+
+	// 1. Remove users who are no longer mentioned
+	previous := make(map[string]bool, len(previousIDs))
+	var removedIDs []string
+	for _, userID := range previousIDs {
+		previous[userID] = true
+		if _, ok := users[userID]; !ok {
+			removedIDs = append(removedIDs, userID)
+		}
+	}
+
+	if len(removedIDs) > 0 {
+		if err := service.mentionRepo.DeleteByMessageIDAndUserIDs(ctx, message.ID, removedIDs); err != nil {
+			log.Printf("Failed to remove message mentions %s: %v", message.ID, err)
+		}
+	}
+
+	// 2. Add the newly mentioned users
+	added := make(map[string]string)
+	for userID, mentionType := range users {
+		if !previous[userID] {
+			added[userID] = mentionType
+		}
+	}
+
+	service.saveMentions(ctx, message, added)
+	return added
+}
+
+// notifyMentions sends a mention event to each mentioned user
+func (service *messageServiceImpl) notifyMentions(message *domain.Message, users map[string]string) {
+	if service.hub == nil {
+		return
+	}
+
+	for userID, mentionType := range users {
+		wsMessage := websocket.WSMessage{
+			Event: websocket.EventMention,
+			ConversationID: message.ConversationID,
+			Data: websocket.MentionData{
+				MessageID: message.ID,
+				SenderID: message.SenderID,
+				Type: mentionType,
+			},
+		}
+
+		jsonData, err := json.Marshal(wsMessage)
+		if err != nil {
+			log.Printf("Failed to marshal %s event: %v", websocket.EventMention, err)
+			return
+		}
+
+		service.hub.SendToUser(userID, jsonData)
+	}
+}
+
+// broadcastToConversation sends a WebSocket event to all online participants of a conversation
+func (service *messageServiceImpl) broadcastToConversation(conv *domain.Conversation, event string, data interface{}) {
+	if service.hub == nil {
+		return
+	}
+
+	wsMessage := websocket.WSMessage{
+		Event: event,
+		ConversationID: conv.ID,
+		Data: data,
+	}
+
+	jsonData, err := json.Marshal(wsMessage)
+	if err != nil {
+		log.Printf("Failed to marshal %s event: %v", event, err)
+		return
+	}
+
+	participantIDs := make([]string, 0, len(conv.Participants))
+	for _, participant := range conv.Participants {
+		participantIDs = append(participantIDs, participant.UserID)
+	}
+
+	service.hub.SendToUsers(participantIDs, jsonData)
+}
+
+// loadSenderPresence fills the live presence of message senders from Redis
+// Presence is best effort: on failure senders are shown as offline
+func (service *messageServiceImpl) loadSenderPresence(ctx context.Context, messages ...*domain.Message) {
+	senders := make([]*domain.User, len(messages))
+	for idx, message := range messages {
+		senders[idx] = &message.Sender
+	}
+
+	if err := service.presenceRepo.Load(ctx, senders...); err != nil {
+		log.Printf("Failed to load presence: %v", err)
+	}
+}
+
+// hideSenderPresence clears the senders' presence if the viewer may not see it (and an expired custom status)
+// Contacts are best effort too: on failure "contacts only" senders are shown as offline
+func (service *messageServiceImpl) hideSenderPresence(ctx context.Context, viewerID string, messages ...*domain.Message) {
+	contactIDs, err := service.contactRepo.FindContactIDs(ctx, viewerID)
+	if err != nil {
+		log.Printf("Failed to load contacts: %v", err)
+	}
+
+	isContact := make(map[string]bool, len(contactIDs))
+	for _, contactID := range contactIDs {
+		isContact[contactID] = true
+	}
+
+	for _, message := range messages {
+		if !message.Sender.CanSeePresence(viewerID, isContact[message.SenderID]) {
+			message.Sender.HidePresence()
+		}
+		message.Sender.ClearExpiredStatus(time.Now())
+	}
+}
@@ -0,0 +1,135 @@
+package upload
+
+import (
+	"chatapp-api/models/domain"
+	"context"
+	"log"
+	"time"
+)
+
+// garbageCollectionBatch is how many attachments or stored files are looked at per query
+const garbageCollectionBatch = 100
+
+// GarbageCollectionResult sums up a garbage collection of orphaned uploads
+type GarbageCollectionResult struct {
+	DryRun      bool
+	Attachments int   // Orphaned attachments removed (would be removed in a dry run)
+	Bytes       int64 // Their total size
+	Files       int   // Files deleted from the object store, thumbnails not counted
+}
+
+// CollectGarbage removes attachments that nothing used for the grace period (never sent, sent with a deleted message),
+// releasing their stored files, then retries deleting files whose deletion failed before.
+// With dryRun nothing is removed, what would be is only logged
+func (service *uploadServiceImpl) CollectGarbage(ctx context.Context, dryRun bool) (*GarbageCollectionResult, error) {
+	result := &GarbageCollectionResult{DryRun: dryRun}
+	createdBefore := time.Now().Add(-time.Duration(service.config.Storage.GCGraceHours) * time.Hour)
+
+	// 1. Orphaned attachments, by batches in ID order
+	afterID := ""
+	for {
+		attachments, err := service.attachmentRepo.FindOrphaned(ctx, createdBefore, afterID, garbageCollectionBatch)
+		if err != nil {
+			return result, err
+		}
+
+		for _, attachment := range attachments {
+			afterID = attachment.ID
+			if err := service.collectAttachment(ctx, &attachment, createdBefore, result); err != nil {
+				return result, err
+			}
+		}
+
+		if len(attachments) < garbageCollectionBatch {
+			break
+		}
+	}
+
+	// 2. Stored files left without reference by a failed deletion
+	afterKey := ""
+	for {
+		objects, err := service.storedObjectRepo.FindUnreferenced(ctx, time.Now(), afterKey, garbageCollectionBatch)
+		if err != nil {
+			return result, err
+		}
+
+		for _, object := range objects {
+			afterKey = object.ObjectKey
+			if dryRun {
+				log.Printf("Garbage collection: would delete unreferenced file %s (%d bytes)", object.ObjectKey, object.Size)
+				continue
+			}
+
+			deleted, err := service.storedObjectRepo.DeleteUnreferenced(ctx, object.ObjectKey, func() error {
+				return service.deleteStoredFiles(ctx, object.ObjectKey)
+			})
+			if err != nil {
+				log.Printf("Garbage collection: failed to delete unreferenced file %s: %v", object.ObjectKey, err)
+				continue
+			}
+			if deleted {
+				log.Printf("Garbage collection: deleted unreferenced file %s (%d bytes)", object.ObjectKey, object.Size)
+				result.Files++
+			}
+		}
+
+		if len(objects) < garbageCollectionBatch {
+			break
+		}
+	}
+
+	log.Printf("Garbage collection (dry run: %t): %d orphaned attachments (%d bytes), %d files deleted",
+		dryRun, result.Attachments, result.Bytes, result.Files)
+	return result, nil
+}
+
+// collectAttachment removes an orphaned attachment and releases its stored file
+func (service *uploadServiceImpl) collectAttachment(ctx context.Context, attachment *domain.Attachment, createdBefore time.Time, result *GarbageCollectionResult) error {
+	if result.DryRun {
+		log.Printf("Garbage collection: would remove attachment %s (%s, %d bytes, uploaded by %s at %s)",
+			attachment.ID, attachment.ObjectKey, attachment.Size, attachment.UploaderID, attachment.CreatedAt.Format(time.RFC3339))
+		result.Attachments++
+		result.Bytes += attachment.Size
+		return nil
+	}
+
+	// 1. Delete the attachment unless it was used meanwhile (e.g. sent just now)
+	deleted, err := service.attachmentRepo.DeleteIfOrphaned(ctx, attachment.ID, createdBefore)
+	if err != nil || !deleted {
+		return err
+	}
+	log.Printf("Garbage collection: removed attachment %s (%s, %d bytes, uploaded by %s at %s)",
+		attachment.ID, attachment.ObjectKey, attachment.Size, attachment.UploaderID, attachment.CreatedAt.Format(time.RFC3339))
+	result.Attachments++
+	result.Bytes += attachment.Size
+
+	// 2. Release its file, deleted with its thumbnails if no other attachment uses it
+	err = service.storedObjectRepo.Release(ctx, attachment.ObjectKey, func() error {
+		if err := service.deleteStoredFiles(ctx, attachment.ObjectKey); err != nil {
+			return err
+		}
+		result.Files++
+		return nil
+	})
+	if err != nil {
+		log.Printf("Garbage collection: failed to release file %s: %v", attachment.ObjectKey, err)
+	}
+	return nil
+}
+
+// RunGarbageCollection periodically collects orphaned uploads (blocking, run in a goroutine).
+// Does nothing when the interval is 0, garbage collection is then run with the "gc" command
+func (service *uploadServiceImpl) RunGarbageCollection() {
+	if service.config.Storage.GCIntervalMins <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(service.config.Storage.GCIntervalMins) * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := service.CollectGarbage(context.Background(), service.config.Storage.GCDryRun); err != nil {
+			log.Printf("Failed to collect orphaned uploads: %v", err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// exifHeader starts the EXIF data of a JPEG APP1 segment
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientationTag is the EXIF tag telling how the image must be rotated/flipped for display
+const exifOrientationTag = 0x0112
+
+// imageOrientation returns the EXIF orientation (1-8) of a JPEG or PNG, 1 (as stored) when there is none
+func imageOrientation(mimeType string, data []byte) int {
+	var orientation int
+	switch mimeType {
+	case "image/jpeg":
+		forEachJPEGSegment(data, func(marker byte, segment []byte) {
+			if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+				orientation = tiffOrientation(segment[len(exifHeader):])
+			}
+		})
+	case "image/png":
+		forEachPNGChunk(data, func(chunkType string, chunk []byte) {
+			if chunkType == "eXIf" {
+				orientation = tiffOrientation(chunk)
+			}
+		})
+	}
+
+	if orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
+
+// stripImageMetadata removes EXIF (GPS included), XMP, IPTC and comments from a JPEG or PNG without re-encoding it.
+// The orientation is kept in a minimal EXIF block, so the image still displays the right way up.
+// Returns the data unchanged if it can't be parsed
+func stripImageMetadata(mimeType string, data []byte, orientation int) []byte {
+	switch mimeType {
+	case "image/jpeg":
+		return stripJPEGMetadata(data, orientation)
+	case "image/png":
+		return stripPNGMetadata(data, orientation)
+	default:
+		return data
+	}
+}
+
+// stripJPEGMetadata keeps APP0 (JFIF), the ICC color profile (APP2), APP14 (Adobe color transform) and the image segments
+func stripJPEGMetadata(data []byte, orientation int) []byte {
+	var stripped bytes.Buffer
+	stripped.Write(data[:2]) // SOI
+
+	var insertedOrientation bool
+	insertOrientation := func() {
+		if !insertedOrientation && orientation > 1 {
+			writeJPEGSegment(&stripped, 0xE1, append(append([]byte{}, exifHeader...), orientationTIFF(orientation)...))
+		}
+		insertedOrientation = true
+	}
+
+	ok := forEachJPEGSegment(data, func(marker byte, segment []byte) {
+		switch {
+		case marker == 0xE0:
+			// JFIF must stay the first segment, the orientation goes right after it
+			writeJPEGSegment(&stripped, marker, segment)
+			insertOrientation()
+		case (marker == 0xE2 && bytes.HasPrefix(segment, []byte("ICC_PROFILE\x00"))) || marker == 0xEE:
+			insertOrientation()
+			writeJPEGSegment(&stripped, marker, segment)
+		case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+			// APP1 (EXIF, XMP), APP2 (multi-picture index), APP13 (IPTC) and other application data, COM (comments)
+		case marker == 0xDA:
+			// Start of scan: the compressed image follows until the end of image
+			insertOrientation()
+			stripped.Write(segment)
+		default:
+			insertOrientation()
+			writeJPEGSegment(&stripped, marker, segment)
+		}
+	})
+
+	if !ok {
+		return data
+	}
+	return stripped.Bytes()
+}
+
+// forEachJPEGSegment calls fn with each segment's marker and payload (without length).
+// For the start of scan (0xDA), the payload is the rest of the image up to the end of image, markers included.
+// Returns false if the data isn't a well-formed JPEG
+func forEachJPEGSegment(data []byte, fn func(marker byte, segment []byte)) bool {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return false
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return false
+		}
+		marker := data[pos+1]
+
+		switch {
+		case marker == 0xFF:
+			// Fill byte
+			pos++
+			continue
+		case marker == 0xDA:
+			// Anything after the end of image (e.g. extra pictures with their own EXIF) is dropped
+			end := bytes.Index(data[pos:], []byte{0xFF, 0xD9})
+			if end < 0 {
+				return false
+			}
+			fn(marker, data[pos:pos+end+2])
+			return true
+		case marker == 0xD9:
+			return true
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without payload
+			pos += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return false
+		}
+		fn(marker, data[pos+4:end])
+		pos = end
+	}
+	return false
+}
+
+// writeJPEGSegment writes a segment with its marker and length
+func writeJPEGSegment(buffer *bytes.Buffer, marker byte, segment []byte) {
+	buffer.Write([]byte{0xFF, marker, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)})
+	buffer.Write(segment)
+}
+
+// stripPNGMetadata removes the eXIf, tEXt, iTXt (XMP), zTXt and tIME chunks
+func stripPNGMetadata(data []byte, orientation int) []byte {
+	var stripped bytes.Buffer
+	stripped.Write(pngSignature)
+
+	ok := forEachPNGChunk(data, func(chunkType string, chunk []byte) {
+		switch chunkType {
+		case "eXIf", "tEXt", "iTXt", "zTXt", "tIME":
+			return
+		}
+
+		writePNGChunk(&stripped, chunkType, chunk)
+
+		// The orientation goes right after the header
+		if chunkType == "IHDR" && orientation > 1 {
+			writePNGChunk(&stripped, "eXIf", orientationTIFF(orientation))
+		}
+	})
+
+	if !ok {
+		return data
+	}
+	return stripped.Bytes()
+}
+
+// forEachPNGChunk calls fn with each chunk's type and data. Returns false if the data isn't a well-formed PNG
+func forEachPNGChunk(data []byte, fn func(chunkType string, chunk []byte)) bool {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return false
+	}
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if end < pos || end > len(data) {
+			return false
+		}
+
+		chunkType := string(data[pos+4 : pos+8])
+		fn(chunkType, data[pos+8:pos+8+length])
+		pos = end
+
+		if chunkType == "IEND" {
+			return true
+		}
+	}
+	return false
+}
+
+// writePNGChunk writes a chunk with its length and CRC
+func writePNGChunk(buffer *bytes.Buffer, chunkType string, chunk []byte) {
+	binary.Write(buffer, binary.BigEndian, uint32(len(chunk)))
+	buffer.WriteString(chunkType)
+	buffer.Write(chunk)
+	binary.Write(buffer, binary.BigEndian, crc32.ChecksumIEEE(append([]byte(chunkType), chunk...)))
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of TIFF-formatted EXIF data (0 if missing)
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for idx := 0; idx < count; idx++ {
+		entry := ifd + 2 + idx*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// orientationTIFF builds minimal TIFF-formatted EXIF data holding only the orientation tag
+func orientationTIFF(orientation int) []byte {
+	tiff := make([]byte, 26)
+	copy(tiff, "MM")
+	binary.BigEndian.PutUint16(tiff[2:], 42)                  // TIFF magic number
+	binary.BigEndian.PutUint32(tiff[4:], 8)                   // First IFD right after the header
+	binary.BigEndian.PutUint16(tiff[8:], 1)                   // 1 entry
+	binary.BigEndian.PutUint16(tiff[10:], exifOrientationTag) // Tag
+	binary.BigEndian.PutUint16(tiff[12:], 3)                  // Type SHORT
+	binary.BigEndian.PutUint32(tiff[14:], 1)                  // Count
+	binary.BigEndian.PutUint16(tiff[18:], uint16(orientation))
+	// tiff[22:26] = 0: no next IFD
+	return tiff
+}
+
+// orientImage rotates/flips an image as its EXIF orientation says, so it displays the right way up without EXIF
+func orientImage(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+
+	oriented := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < outHeight; y++ {
+		for x := 0; x < outWidth; x++ {
+			// Source pixel of each output pixel
+			var srcX, srcY int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				srcX, srcY = width-1-x, y
+			case 3: // Rotated 180°
+				srcX, srcY = width-1-x, height-1-y
+			case 4: // Mirrored vertically
+				srcX, srcY = x, height-1-y
+			case 5: // Transposed
+				srcX, srcY = y, x
+			case 6: // Rotated 90° clockwise
+				srcX, srcY = y, height-1-x
+			case 7: // Transversed
+				srcX, srcY = width-1-y, height-1-x
+			case 8: // Rotated 90° counter-clockwise
+				srcX, srcY = width-1-y, x
+			}
+			oriented.SetRGBA(x, y, img.RGBAAt(img.Bounds().Min.X+srcX, img.Bounds().Min.Y+srcY))
+		}
+	}
+	return oriented
+}
@@ -0,0 +1,165 @@
+package upload
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage is a small opaque image to encode
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 30), G: uint8(y * 60), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegWithMetadata encodes a JPEG and inserts metadata segments right after SOI
+func jpegWithMetadata(t *testing.T, segments map[byte][]byte, order []byte) []byte {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var data bytes.Buffer
+	data.Write(encoded.Bytes()[:2])
+	for _, marker := range order {
+		writeJPEGSegment(&data, marker, segments[marker])
+	}
+	data.Write(encoded.Bytes()[2:])
+	return data.Bytes()
+}
+
+// pngWithChunks encodes a PNG and inserts chunks right after IHDR
+func pngWithChunks(t *testing.T, chunks [][2]string) []byte {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, testImage()); err != nil {
+		t.Fatal(err)
+	}
+
+	var data bytes.Buffer
+	forEachPNGChunk(encoded.Bytes(), func(chunkType string, chunk []byte) {
+		if chunkType == "IHDR" {
+			data.Write(pngSignature)
+			writePNGChunk(&data, chunkType, chunk)
+			for _, extra := range chunks {
+				writePNGChunk(&data, extra[0], []byte(extra[1]))
+			}
+			return
+		}
+		writePNGChunk(&data, chunkType, chunk)
+	})
+	return data.Bytes()
+}
+
+func TestStripImageMetadata(t *testing.T) {
+	gpsExif := append(append([]byte{}, exifHeader...), orientationTIFF(6)...)
+	gpsExif = append(gpsExif, []byte("GPSLatitude 48.8584 GPSLongitude 2.2945")...)
+
+	tests := []struct {
+		name            string
+		mimeType        string
+		data            []byte
+		orientation     int
+		mustNotContain  []string
+		wantOrientation int
+	}{
+		{
+			name:     "JPEG: EXIF, XMP and comment removed",
+			mimeType: "image/jpeg",
+			data: jpegWithMetadata(t, map[byte][]byte{
+				0xE1: gpsExif,
+				0xFE: []byte("secret comment"),
+				0xED: []byte("Photoshop 3.0\x00IPTC by-line"),
+			}, []byte{0xE1, 0xFE, 0xED}),
+			orientation:     1,
+			mustNotContain:  []string{"GPSLatitude", "secret comment", "IPTC by-line"},
+			wantOrientation: 1,
+		},
+		{
+			name:     "JPEG: orientation kept in a minimal EXIF block",
+			mimeType: "image/jpeg",
+			data: jpegWithMetadata(t, map[byte][]byte{
+				0xE1: gpsExif,
+			}, []byte{0xE1}),
+			orientation:     6,
+			mustNotContain:  []string{"GPSLatitude"},
+			wantOrientation: 6,
+		},
+		{
+			name:     "PNG: text, XMP, time and EXIF chunks removed",
+			mimeType: "image/png",
+			data: pngWithChunks(t, [][2]string{
+				{"tEXt", "Author\x00Jane"},
+				{"iTXt", "XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>"},
+				{"tIME", "\x07\xe8\x01\x01\x00\x00\x00"},
+				{"eXIf", string(gpsExif[len(exifHeader):])},
+			}),
+			orientation:     1,
+			mustNotContain:  []string{"Jane", "xmpmeta", "tIME", "GPSLatitude"},
+			wantOrientation: 1,
+		},
+		{
+			name:     "PNG: orientation kept in a minimal eXIf chunk",
+			mimeType: "image/png",
+			data: pngWithChunks(t, [][2]string{
+				{"eXIf", string(gpsExif[len(exifHeader):])},
+			}),
+			orientation:     6,
+			mustNotContain:  []string{"GPSLatitude"},
+			wantOrientation: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped := stripImageMetadata(tt.mimeType, tt.data, tt.orientation)
+
+			for _, value := range tt.mustNotContain {
+				if bytes.Contains(stripped, []byte(value)) {
+					t.Errorf("stripped image still contains %q", value)
+				}
+			}
+			if got := imageOrientation(tt.mimeType, stripped); got != tt.wantOrientation {
+				t.Errorf("orientation after stripping = %d, want %d", got, tt.wantOrientation)
+			}
+
+			// The pixels are untouched: it still decodes to the same size
+			img, _, err := image.Decode(bytes.NewReader(stripped))
+			if err != nil {
+				t.Fatalf("stripped image doesn't decode: %v", err)
+			}
+			if img.Bounds().Dx() != 8 || img.Bounds().Dy() != 4 {
+				t.Errorf("stripped image is %dx%d, want 8x4", img.Bounds().Dx(), img.Bounds().Dy())
+			}
+		})
+	}
+}
+
+func TestStripImageMetadataUnparseable(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+	}{
+		{name: "not a JPEG", mimeType: "image/jpeg", data: []byte("plain text")},
+		{name: "truncated JPEG segment", mimeType: "image/jpeg", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x10, 0x00, 'E'}},
+		{name: "not a PNG", mimeType: "image/png", data: []byte("plain text")},
+		{name: "PNG without IEND", mimeType: "image/png", data: append(append([]byte{}, pngSignature...), 0, 0, 0, 0)},
+		{name: "other type", mimeType: "image/gif", data: []byte("GIF89a")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripImageMetadata(tt.mimeType, tt.data, 1); !bytes.Equal(got, tt.data) {
+				t.Errorf("stripImageMetadata() = %q, want the data unchanged", got)
+			}
+		})
+	}
+}
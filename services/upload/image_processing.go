@@ -0,0 +1,232 @@
+package upload
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// thumbnailSizes are the thumbnails generated for images (longest side in pixels)
+// An image not larger than a size gets no thumbnail of that size, the original is small enough
+var thumbnailSizes = []struct {
+	name    string
+	maxSide int
+}{
+	{name: "small", maxSide: 320},
+	{name: "medium", maxSide: 1024},
+}
+
+// thumbnailQuality is the JPEG quality of thumbnails
+const thumbnailQuality = 80
+
+// maxDecodePixels guards against decompression bombs: bigger images only get their dimensions recorded
+const maxDecodePixels = 50_000_000
+
+// blurhashSide is the size the image is reduced to before computing its BlurHash (it only keeps a few colors anyway)
+const blurhashSide = 32
+
+// processedImage is what was extracted from an uploaded image
+type processedImage struct {
+	width      int
+	height     int
+	thumbnails []encodedThumbnail
+	blurhash   string
+}
+
+// encodedThumbnail is a thumbnail ready to be stored
+type encodedThumbnail struct {
+	name   string
+	width  int
+	height int
+	data   []byte
+}
+
+// processImage reads an image's dimensions, then generates its thumbnails and BlurHash.
+// orientation is the EXIF orientation: dimensions, thumbnails and BlurHash are those of the image displayed the right way up.
+// Returns nil if the image can't be decoded (e.g. unsupported or corrupted), the upload is kept as is
+func processImage(file io.ReadSeeker, orientation int) *processedImage {
+	// 1. Dimensions from the header only
+	imageConfig, _, err := image.DecodeConfig(file)
+	file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil
+	}
+
+	result := &processedImage{width: imageConfig.Width, height: imageConfig.Height}
+	if orientation >= 5 {
+		// Rotated by 90°: displayed width and height are swapped
+		result.width, result.height = imageConfig.Height, imageConfig.Width
+	}
+	if imageConfig.Width*imageConfig.Height > maxDecodePixels {
+		return result
+	}
+
+	// 2. Decode the pixels (first frame for GIFs)
+	img, _, err := image.Decode(file)
+	file.Seek(0, io.SeekStart)
+	if err != nil {
+		return result
+	}
+
+	// 3. Thumbnails, flattened on white (JPEG has no transparency)
+	for _, size := range thumbnailSizes {
+		width, height := fitInside(result.width, result.height, size.maxSide)
+		if width >= result.width && height >= result.height {
+			continue
+		}
+
+		var buffer bytes.Buffer
+		err := jpeg.Encode(&buffer, resizeOriented(img, width, height, orientation), &jpeg.Options{Quality: thumbnailQuality})
+		if err != nil {
+			continue
+		}
+		result.thumbnails = append(result.thumbnails, encodedThumbnail{
+			name:   size.name,
+			width:  width,
+			height: height,
+			data:   buffer.Bytes(),
+		})
+	}
+
+	// 4. BlurHash from a tiny copy
+	width, height := fitInside(result.width, result.height, blurhashSide)
+	result.blurhash = encodeBlurhash(resizeOriented(img, width, height, orientation), 4, 3)
+
+	return result
+}
+
+// resizeOriented scales an image to the given displayed size, then rotates/flips it as its EXIF orientation says
+// (resizing first keeps the pixel-by-pixel rotation cheap)
+func resizeOriented(img image.Image, width, height, orientation int) *image.RGBA {
+	if orientation >= 5 {
+		return orientImage(resizeImage(img, height, width), orientation)
+	}
+	return orientImage(resizeImage(img, width, height), orientation)
+}
+
+// fitInside scales dimensions so the longest side is at most maxSide, keeping the aspect ratio
+func fitInside(width, height, maxSide int) (int, int) {
+	if width <= maxSide && height <= maxSide {
+		return width, height
+	}
+	if width >= height {
+		return maxSide, max(1, height*maxSide/width)
+	}
+	return max(1, width*maxSide/height), maxSide
+}
+
+// resizeImage scales an image to the given size on a white background
+func resizeImage(img image.Image, width, height int) *image.RGBA {
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(resized, resized.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return resized
+}
+
+// blurhashCharacters is the base 83 alphabet of BlurHash
+const blurhashCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// encodeBlurhash computes the BlurHash of an image (https://blurha.sh) with xComponents x yComponents colors
+func encodeBlurhash(img *image.RGBA, xComponents, yComponents int) string {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// 1. Average color weighted by each cosine basis function (DC = average color, AC = details)
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1.0
+			}
+
+			var r, g, b float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+					pixel := img.RGBAAt(img.Bounds().Min.X+x, img.Bounds().Min.Y+y)
+					r += basis * sRGBToLinear(pixel.R)
+					g += basis * sRGBToLinear(pixel.G)
+					b += basis * sRGBToLinear(pixel.B)
+				}
+			}
+
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{r * scale, g * scale, b * scale})
+		}
+	}
+
+	// 2. Encode: size flag, quantised maximum AC value, DC, then each AC component
+	var hash strings.Builder
+	hash.WriteString(encodeBase83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maximumValue := 1.0
+	if len(factors) > 1 {
+		actualMaximum := 0.0
+		for _, factor := range factors[1:] {
+			for _, value := range factor {
+				actualMaximum = math.Max(actualMaximum, math.Abs(value))
+			}
+		}
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		hash.WriteString(encodeBase83(quantisedMaximum, 1))
+	} else {
+		hash.WriteString(encodeBase83(0, 1))
+	}
+
+	dc := factors[0]
+	hash.WriteString(encodeBase83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+
+	for _, factor := range factors[1:] {
+		quantised := [3]int{}
+		for idx, value := range factor {
+			quantised[idx] = int(math.Max(0, math.Min(18, math.Floor(signPow(value/maximumValue, 0.5)*9+9.5))))
+		}
+		hash.WriteString(encodeBase83(quantised[0]*19*19+quantised[1]*19+quantised[2], 2))
+	}
+
+	return hash.String()
+}
+
+// encodeBase83 encodes a value on length base 83 characters
+func encodeBase83(value, length int) string {
+	encoded := make([]byte, length)
+	for idx := 0; idx < length; idx++ {
+		digit := (value / int(math.Pow(83, float64(length-idx-1)))) % 83
+		encoded[idx] = blurhashCharacters[digit]
+	}
+	return string(encoded)
+}
+
+// sRGBToLinear converts an sRGB channel (0-255) to linear light (0-1)
+func sRGBToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts linear light (0-1) to an sRGB channel (0-255)
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow raises the absolute value to exp, keeping the sign
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,58 @@
+package upload
+
+import (
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// scanFile checks a file with the malware scanner before it is stored, then seeks it back to its start.
+// An infected file is rejected (MalwareDetectedError). When the scanner is unavailable the upload fails,
+// unless SCANNER_FAIL_OPEN accepts it with the "failed" status. A file only checked by the test scanner is "unscanned".
+// Returns the scan status to record on the attachment
+func (service *uploadServiceImpl) scanFile(ctx context.Context, userID string, filename string, file io.ReadSeeker) (string, error) {
+	// 1. Scan the whole content
+	result, err := service.scanner.Scan(ctx, file)
+	if _, seekErr := file.Seek(0, io.SeekStart); seekErr != nil {
+		return "", exceptions.NewInternalServerError("Failed to read file")
+	}
+
+	// 2. Scanner unavailable
+	if err != nil {
+		log.Printf("Failed to scan upload %q of user %s with %s: %v", filename, userID, service.scanner.Name(), err)
+		if service.config.Scanner.FailOpen {
+			return domain.ScanStatusFailed, nil
+		}
+		return "", exceptions.NewInternalServerError("Failed to scan file for malware, try again later")
+	}
+
+	// 3. Infected files are never stored
+	if result.Infected {
+		log.Printf("Rejected infected upload %q of user %s: %s (%s)", filename, userID, result.Signature, service.scanner.Name())
+		return "", exceptions.NewMalwareDetectedError(
+			fmt.Sprintf("File rejected: malware detected (%s)", result.Signature))
+	}
+
+	// 4. Only a thorough scanner can tell the file is clean
+	if !service.scanner.Thorough() {
+		return domain.ScanStatusUnscanned, nil
+	}
+	return domain.ScanStatusClean, nil
+}
+
+// recordScan sets the scan result of a file on its attachment (an unscanned file has no scanner nor scan time)
+func (service *uploadServiceImpl) recordScan(attachment *domain.Attachment, scanStatus string) {
+	attachment.ScanStatus = scanStatus
+	if scanStatus == domain.ScanStatusUnscanned {
+		return
+	}
+
+	scannedBy := service.scanner.Name()
+	scannedAt := time.Now()
+	attachment.ScannedBy = &scannedBy
+	attachment.ScannedAt = &scannedAt
+}
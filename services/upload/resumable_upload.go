@@ -0,0 +1,518 @@
+package upload
+
+import (
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"chatapp-api/models/web"
+	resumableUploadRepo "chatapp-api/repositories/resumable_upload"
+	"chatapp-api/utils"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// resumableCleanupInterval is how often expired resumable uploads are looked for
+const resumableCleanupInterval = 15 * time.Minute
+
+// resumableCleanupBatch is how many expired uploads are removed per query
+const resumableCleanupBatch = 100
+
+// resumableLockLease is how long a request holds an upload's lease without renewing it. The lease is renewed
+// while the request runs, so this only delays other requests when an instance dies holding it
+const resumableLockLease = time.Minute
+
+// CreateResumableUpload starts a resumable (tus) upload, its bytes are then sent with WriteResumableChunk
+func (service *uploadServiceImpl) CreateResumableUpload(ctx context.Context, userID string, req *web.CreateResumableUploadRequest) (*web.ResumableUploadResponse, error) {
+	// 1. Validate the total size
+	maxSize := int64(service.config.Storage.ResumableMaxSizeMB) * 1024 * 1024
+	if req.Length > maxSize {
+		return nil, exceptions.NewPayloadTooLargeError(
+			fmt.Sprintf("File too large. Maximum size is %d MB", service.config.Storage.ResumableMaxSizeMB))
+	}
+
+	// 2. Check the user's storage quota and upload rate now rather than after the whole file is sent
+	// (only a check: the upload is reserved once complete)
+	if err := service.checkUploadLimits(ctx, userID, req.Length); err != nil {
+		return nil, err
+	}
+
+	// 3. Read the options sent in Upload-Metadata
+	metadata, err := parseUploadMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &domain.ResumableUpload{
+		UploaderID: userID,
+		Filename: metadata["filename"],
+		Length: req.Length,
+		ExpiresAt: service.resumableExpiry(),
+	}
+	if upload.Filename == "" {
+		upload.Filename = "upload"
+	}
+	if value, ok := metadata["keep_metadata"]; ok && value != "false" {
+		upload.KeepMetadata = true
+	}
+	if value, ok := metadata["duration_ms"]; ok {
+		durationMs, err := strconv.Atoi(value)
+		if err != nil || durationMs < 0 {
+			return nil, exceptions.NewBadRequestError("Invalid duration_ms in Upload-Metadata")
+		}
+		upload.DurationMs = &durationMs
+	}
+
+	// 4. Save the upload, then create its empty partial file
+	if err := service.resumableUploadRepo.Create(ctx, upload); err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to create upload")
+	}
+
+	if err := os.MkdirAll(service.config.Storage.ResumableDir, 0o755); err != nil {
+		log.Printf("Failed to create resumable upload folder: %v", err)
+		return nil, exceptions.NewInternalServerError("Failed to create upload")
+	}
+	partial, err := os.Create(service.partialPath(upload.ID))
+	if err != nil {
+		log.Printf("Failed to create partial file of upload %s: %v", upload.ID, err)
+		return nil, exceptions.NewInternalServerError("Failed to create upload")
+	}
+	partial.Close()
+
+	return toResumableUploadResponse(upload, nil), nil
+}
+
+// GetResumableUpload returns the state of one of the user's resumable uploads (with its attachment once complete)
+func (service *uploadServiceImpl) GetResumableUpload(ctx context.Context, userID, uploadID string) (*web.ResumableUploadResponse, error) {
+	upload, err := service.findResumableUpload(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !upload.IsComplete() {
+		return toResumableUploadResponse(upload, nil), nil
+	}
+
+	attachment, err := service.attachmentRepo.FindByID(ctx, *upload.AttachmentID)
+	if err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to get attachment")
+	}
+	return toResumableUploadResponse(upload, attachment), nil
+}
+
+// WriteResumableChunk appends a chunk at the current offset of a resumable upload.
+// Once all bytes are received the file goes through the same checks and storage as UploadFile
+func (service *uploadServiceImpl) WriteResumableChunk(ctx context.Context, userID, uploadID string, req *web.PatchResumableUploadRequest, chunk io.Reader) (*web.ResumableUploadResponse, error) {
+	// 1. Find the upload
+	if _, err := service.findResumableUpload(ctx, userID, uploadID); err != nil {
+		return nil, err
+	}
+
+	// 2. One chunk at a time per upload, whichever API instance receives it. The upload's writes don't end
+	// with the request: the offset of a chunk interrupted by a disconnection must still be saved
+	writeCtx := context.WithoutCancel(ctx)
+	token, unlock, err := service.lockResumableUpload(writeCtx, uploadID)
+	if errors.Is(err, resumableUploadRepo.ErrUploadLocked) {
+		return nil, exceptions.NewConflictError("Another chunk of this upload is being received")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// 3. Read its state again now that no other request writes it
+	upload, err := service.findResumableUpload(writeCtx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return service.writeLockedChunk(writeCtx, upload, token, req, chunk)
+}
+
+// writeLockedChunk writes a chunk of an upload whose lease the token holds
+func (service *uploadServiceImpl) writeLockedChunk(ctx context.Context, upload *domain.ResumableUpload, token string, req *web.PatchResumableUploadRequest, chunk io.Reader) (*web.ResumableUploadResponse, error) {
+	// 1. Already complete: nothing more to receive (e.g. the response of the last chunk was lost)
+	if upload.IsComplete() {
+		if *req.Offset != upload.Length {
+			return nil, exceptions.NewConflictError("Upload is already complete")
+		}
+		attachment, err := service.attachmentRepo.FindByID(ctx, *upload.AttachmentID)
+		if err != nil {
+			return nil, exceptions.NewInternalServerError("Failed to get attachment")
+		}
+		return toResumableUploadResponse(upload, attachment), nil
+	}
+
+	// 2. The chunk must start where the previous one ended
+	if *req.Offset != upload.Offset {
+		return nil, exceptions.NewConflictError(
+			fmt.Sprintf("Upload-Offset %d doesn't match the current offset %d", *req.Offset, upload.Offset))
+	}
+
+	// 3. Append the chunk. Bytes received before a disconnection are kept, the client resumes from there
+	written, err := service.appendChunk(upload, chunk)
+	if written > 0 {
+		upload.Offset += written
+		upload.ExpiresAt = service.resumableExpiry()
+		err := service.resumableUploadRepo.UpdateOffset(ctx, upload.ID, token, upload.Offset, upload.ExpiresAt)
+		if errors.Is(err, resumableUploadRepo.ErrUploadLocked) {
+			return nil, exceptions.NewConflictError("Another request took over this upload, resume from the current offset")
+		}
+		if err != nil {
+			return nil, exceptions.NewInternalServerError("Failed to save upload offset")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Last chunk: store the file and create the attachment
+	if upload.Offset < upload.Length {
+		return toResumableUploadResponse(upload, nil), nil
+	}
+	attachment, err := service.completeResumableUpload(ctx, upload, token)
+	if err != nil {
+		return nil, err
+	}
+	return toResumableUploadResponse(upload, attachment), nil
+}
+
+// appendChunk writes a chunk at the end of the partial file and returns the number of bytes written
+func (service *uploadServiceImpl) appendChunk(upload *domain.ResumableUpload, chunk io.Reader) (int64, error) {
+	partial, err := os.OpenFile(service.partialPath(upload.ID), os.O_WRONLY, 0o644)
+	if os.IsNotExist(err) {
+		return 0, exceptions.NewNotFoundError("Upload not found or expired")
+	}
+	if err != nil {
+		log.Printf("Failed to open partial file of upload %s: %v", upload.ID, err)
+		return 0, exceptions.NewInternalServerError("Failed to write upload")
+	}
+	defer partial.Close()
+
+	// 1. The bytes received so far must be here: another API instance may have received them
+	info, err := partial.Stat()
+	if err != nil {
+		return 0, exceptions.NewInternalServerError("Failed to write upload")
+	}
+	if info.Size() < upload.Offset {
+		log.Printf("Partial file of upload %s has %d of its %d bytes received: STORAGE_RESUMABLE_DIR must be shared by all API instances",
+			upload.ID, info.Size(), upload.Offset)
+		return 0, exceptions.NewInternalServerError("Received bytes of this upload are unavailable")
+	}
+
+	// 2. Drop bytes written after the saved offset (a chunk interrupted before its offset was saved)
+	if err := partial.Truncate(upload.Offset); err != nil {
+		return 0, exceptions.NewInternalServerError("Failed to write upload")
+	}
+	if _, err := partial.Seek(upload.Offset, io.SeekStart); err != nil {
+		return 0, exceptions.NewInternalServerError("Failed to write upload")
+	}
+
+	// 3. Copy the chunk, never past Upload-Length
+	remaining := upload.Length - upload.Offset
+	written, err := io.Copy(partial, io.LimitReader(chunk, remaining))
+	if err != nil {
+		log.Printf("Chunk of upload %s interrupted after %d bytes: %v", upload.ID, written, err)
+		return written, exceptions.NewBadRequestError("Upload interrupted, resume from the current offset")
+	}
+
+	// 4. Reject a chunk going past Upload-Length (the bytes up to the length are kept)
+	if written == remaining {
+		if extra, _ := chunk.Read(make([]byte, 1)); extra > 0 {
+			return written, exceptions.NewBadRequestError("Chunk exceeds Upload-Length")
+		}
+	}
+	return written, nil
+}
+
+// completeResumableUpload checks the received file like UploadFile does, stores it and records the attachment.
+// A file that fails the checks is discarded; after a storage or quota error it is kept so a retry can complete it.
+// The token holds the upload's lease
+func (service *uploadServiceImpl) completeResumableUpload(ctx context.Context, upload *domain.ResumableUpload, token string) (*domain.Attachment, error) {
+	// 1. Open the received file
+	partial, err := os.Open(service.partialPath(upload.ID))
+	if err != nil {
+		log.Printf("Failed to open partial file of upload %s: %v", upload.ID, err)
+		return nil, exceptions.NewInternalServerError("Failed to read upload")
+	}
+	defer partial.Close()
+
+	// 2. Same MIME sniffing and allowlist as UploadFile
+	mimetype, err := detectMimeType(partial)
+	if err != nil {
+		service.discardResumableUpload(ctx, upload.ID, token)
+		return nil, err
+	}
+
+	// 3. Images are processed in memory, they keep the direct upload limit
+	if strings.HasPrefix(mimetype, "image/") && upload.Length > maxFileSize {
+		service.discardResumableUpload(ctx, upload.ID, token)
+		return nil, exceptions.NewPayloadTooLargeError(
+			fmt.Sprintf("Image too large. Maximum size is %d MB", maxFileSize/(1024*1024)))
+	}
+
+	// 4. Reserve room in the limits, other uploads may have completed meanwhile.
+	// The file is kept so the upload can be completed once the user has room again
+	reservationID, err := service.reserveUpload(ctx, upload.UploaderID, upload.Length)
+	if err != nil {
+		return nil, err
+	}
+	defer service.releaseUpload(ctx, reservationID)
+
+	// 5. Scan it for malware: an infected upload is discarded, the file is kept if the scanner is unavailable
+	scanStatus, err := service.scanFile(ctx, upload.UploaderID, upload.Filename, partial)
+	if err != nil {
+		if _, infected := err.(exceptions.MalwareDetectedError); infected {
+			service.discardResumableUpload(ctx, upload.ID, token)
+		}
+		return nil, err
+	}
+
+	// 6. Store it through the object store and record the attachment
+	options := &web.UploadFileRequest{DurationMs: upload.DurationMs, KeepMetadata: upload.KeepMetadata}
+	attachment, err := service.storeAttachment(ctx, upload.UploaderID, partial, upload.Filename, upload.Length, mimetype, scanStatus, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. Link the upload to its attachment (kept until it expires so the client can fetch it), drop the partial file
+	if err := service.resumableUploadRepo.MarkCompleted(ctx, upload.ID, token, attachment.ID); err != nil {
+		log.Printf("Failed to mark upload %s as complete: %v", upload.ID, err)
+	}
+	upload.AttachmentID = &attachment.ID
+	service.removePartialFile(upload.ID)
+
+	return attachment, nil
+}
+
+// DeleteResumableUpload cancels one of the user's resumable uploads (tus termination)
+func (service *uploadServiceImpl) DeleteResumableUpload(ctx context.Context, userID, uploadID string) error {
+	if _, err := service.findResumableUpload(ctx, userID, uploadID); err != nil {
+		return err
+	}
+
+	token, unlock, err := service.lockResumableUpload(ctx, uploadID)
+	if errors.Is(err, resumableUploadRepo.ErrUploadLocked) {
+		return exceptions.NewConflictError("A chunk of this upload is being received")
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// The attachment of a complete upload isn't affected
+	if err := service.resumableUploadRepo.Delete(ctx, uploadID, token); err != nil {
+		return exceptions.NewInternalServerError("Failed to delete upload")
+	}
+	service.removePartialFile(uploadID)
+	return nil
+}
+
+// RunResumableCleanup periodically removes expired resumable uploads and their partial files (blocking, run in a goroutine)
+func (service *uploadServiceImpl) RunResumableCleanup() {
+	ticker := time.NewTicker(resumableCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		removed, err := service.cleanupResumableUploads(context.Background())
+		if err != nil {
+			log.Printf("Failed to clean up expired resumable uploads: %v", err)
+		}
+		if removed > 0 {
+			log.Printf("Removed %d expired resumable uploads", removed)
+		}
+	}
+}
+
+// cleanupResumableUploads removes expired uploads, then partial files left without an upload (e.g. a crash between
+// the two deletes). Returns the number of uploads removed
+func (service *uploadServiceImpl) cleanupResumableUploads(ctx context.Context) (int, error) {
+	now := time.Now()
+	removed := 0
+
+	// 1. Expired uploads, in batches
+	for {
+		uploads, err := service.resumableUploadRepo.FindExpired(ctx, now, resumableCleanupBatch)
+		if err != nil {
+			return removed, err
+		}
+
+		batchRemoved := 0
+		for _, upload := range uploads {
+			// An upload receiving a chunk is left for the next run
+			token, unlock, err := service.lockResumableUpload(ctx, upload.ID)
+			if err != nil {
+				continue
+			}
+			err = service.resumableUploadRepo.Delete(ctx, upload.ID, token)
+			unlock()
+			if err != nil {
+				return removed, err
+			}
+			service.removePartialFile(upload.ID)
+			batchRemoved++
+		}
+		removed += batchRemoved
+
+		// Stop on a batch of uploads all being written, the next query would return them again
+		if len(uploads) < resumableCleanupBatch || batchRemoved == 0 {
+			break
+		}
+	}
+
+	// 2. Stale partial files whose upload no longer exists
+	entries, err := os.ReadDir(service.config.Storage.ResumableDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return removed, nil
+		}
+		return removed, err
+	}
+
+	staleBefore := now.Add(-time.Duration(service.config.Storage.ResumableExpiryHours) * time.Hour)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(staleBefore) {
+			continue
+		}
+
+		_, err = service.resumableUploadRepo.FindByID(ctx, entry.Name())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			service.removePartialFile(entry.Name())
+		}
+	}
+
+	return removed, nil
+}
+
+// findResumableUpload finds an unexpired upload of the user (someone else's upload is reported as not found)
+func (service *uploadServiceImpl) findResumableUpload(ctx context.Context, userID, uploadID string) (*domain.ResumableUpload, error) {
+	upload, err := service.resumableUploadRepo.FindByID(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Upload not found or expired")
+		}
+		return nil, err
+	}
+
+	if upload.UploaderID != userID || time.Now().After(upload.ExpiresAt) {
+		return nil, exceptions.NewNotFoundError("Upload not found or expired")
+	}
+	return upload, nil
+}
+
+// discardResumableUpload removes an upload whose file was rejected, the token holds its lease
+func (service *uploadServiceImpl) discardResumableUpload(ctx context.Context, uploadID, token string) {
+	if err := service.resumableUploadRepo.Delete(ctx, uploadID, token); err != nil {
+		log.Printf("Failed to delete rejected upload %s: %v", uploadID, err)
+	}
+	service.removePartialFile(uploadID)
+}
+
+// lockResumableUpload takes the lease of an upload for the request, renewed in the background until unlock is called.
+// Returns the token to pass to the upload's writes, ErrUploadLocked if another request holds it
+func (service *uploadServiceImpl) lockResumableUpload(ctx context.Context, uploadID string) (string, func(), error) {
+	token := utils.GenerateID("lck")
+	err := service.resumableUploadRepo.Lock(ctx, uploadID, token, time.Now().Add(resumableLockLease))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, exceptions.NewNotFoundError("Upload not found or expired")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Renew the lease until unlocked (a chunk can take minutes to receive, a file minutes to scan and store),
+	// then release it even if the request was cancelled
+	leaseCtx := context.WithoutCancel(ctx)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(resumableLockLease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := service.resumableUploadRepo.ExtendLock(leaseCtx, uploadID, token, time.Now().Add(resumableLockLease))
+				if err != nil {
+					log.Printf("Failed to renew the lease of upload %s: %v", uploadID, err)
+				}
+			}
+		}
+	}()
+
+	unlock := func() {
+		close(stop)
+		<-stopped
+		if err := service.resumableUploadRepo.Unlock(leaseCtx, uploadID, token); err != nil {
+			log.Printf("Failed to release the lease of upload %s: %v", uploadID, err)
+		}
+	}
+	return token, unlock, nil
+}
+
+// removePartialFile deletes the received bytes of an upload
+func (service *uploadServiceImpl) removePartialFile(uploadID string) {
+	if err := os.Remove(service.partialPath(uploadID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to delete partial file of upload %s: %v", uploadID, err)
+	}
+}
+
+// partialPath returns the path of the file holding the bytes received for an upload
+func (service *uploadServiceImpl) partialPath(uploadID string) string {
+	return filepath.Join(service.config.Storage.ResumableDir, filepath.Base(uploadID))
+}
+
+// resumableExpiry returns when an upload with no new chunk from now on is abandoned
+func (service *uploadServiceImpl) resumableExpiry() time.Time {
+	return time.Now().Add(time.Duration(service.config.Storage.ResumableExpiryHours) * time.Hour)
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header: comma-separated "key base64(value)" pairs,
+// a key alone has an empty value
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, exceptions.NewBadRequestError("Invalid Upload-Metadata header")
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, exceptions.NewBadRequestError(fmt.Sprintf("Invalid Upload-Metadata value for %s", key))
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// toResumableUploadResponse converts an upload (and its attachment once complete) to its response DTO
+func toResumableUploadResponse(upload *domain.ResumableUpload, attachment *domain.Attachment) *web.ResumableUploadResponse {
+	response := &web.ResumableUploadResponse{
+		ID: upload.ID,
+		Filename: upload.Filename,
+		Length: upload.Length,
+		Offset: upload.Offset,
+		ExpiresAt: upload.ExpiresAt,
+	}
+	if attachment != nil {
+		response.Attachment = toUploadResult(attachment)
+	}
+	return response
+}
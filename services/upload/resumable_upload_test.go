@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"chatapp-api/config"
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// interruptedReader returns its data, then fails like a dropped connection
+type interruptedReader struct {
+	data io.Reader
+}
+
+func (reader *interruptedReader) Read(p []byte) (int, error) {
+	n, err := reader.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestAppendChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		partial string // content of the partial file before the chunk, "" for no file
+		offset int64
+		length int64
+		chunk io.Reader
+		wantWritten int64
+		wantFile string
+		wantErr error
+	}{
+		{
+			name: "first chunk",
+			partial: "",
+			offset: 0,
+			length: 10,
+			chunk: strings.NewReader("hello"),
+			wantWritten: 5,
+			wantFile: "hello",
+		},
+		{
+			name: "appended at the offset",
+			partial: "hello",
+			offset: 5,
+			length: 10,
+			chunk: strings.NewReader("world"),
+			wantWritten: 5,
+			wantFile: "helloworld",
+		},
+		{
+			name: "bytes past the saved offset are dropped",
+			partial: "hello-unsaved",
+			offset: 5,
+			length: 10,
+			chunk: strings.NewReader("world"),
+			wantWritten: 5,
+			wantFile: "helloworld",
+		},
+		{
+			name: "chunk going past Upload-Length",
+			partial: "hello",
+			offset: 5,
+			length: 10,
+			chunk: strings.NewReader("world!"),
+			wantWritten: 5,
+			wantFile: "helloworld",
+			wantErr: exceptions.BadRequestError{},
+		},
+		{
+			name: "interrupted chunk keeps the bytes received",
+			partial: "hello",
+			offset: 5,
+			length: 10,
+			chunk: &interruptedReader{data: strings.NewReader("wor")},
+			wantWritten: 3,
+			wantFile: "hellowor",
+			wantErr: exceptions.BadRequestError{},
+		},
+		{
+			name: "received bytes missing (partial file on another instance)",
+			partial: "",
+			offset: 5,
+			length: 10,
+			chunk: strings.NewReader("world"),
+			wantWritten: 0,
+			wantFile: "",
+			wantErr: exceptions.InternalServerError{},
+		},
+		{
+			name: "partial file missing",
+			partial: "-",
+			offset: 0,
+			length: 10,
+			chunk: strings.NewReader("hello"),
+			wantWritten: 0,
+			wantErr: exceptions.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &uploadServiceImpl{config: &config.Config{}}
+			service.config.Storage.ResumableDir = t.TempDir()
+			upload := &domain.ResumableUpload{ID: "upload-1", Offset: tt.offset, Length: tt.length}
+
+			if tt.partial != "-" {
+				if err := os.WriteFile(filepath.Join(service.config.Storage.ResumableDir, upload.ID), []byte(tt.partial), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			written, err := service.appendChunk(upload, tt.chunk)
+			if written != tt.wantWritten {
+				t.Errorf("written = %d, want %d", written, tt.wantWritten)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && fmt.Sprintf("%T", err) != fmt.Sprintf("%T", tt.wantErr) {
+				t.Fatalf("error = %v, want a %T", err, tt.wantErr)
+			}
+			if tt.partial == "-" {
+				return
+			}
+
+			content, err := os.ReadFile(filepath.Join(service.config.Storage.ResumableDir, upload.ID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.wantFile {
+				t.Errorf("partial file = %q, want %q", content, tt.wantFile)
+			}
+		})
+	}
+}
@@ -3,11 +3,42 @@ package upload
 import (
 	"chatapp-api/models/web"
 	"context"
+	"io"
 	"mime/multipart"
 )
 
 // UploadService interface for file upload operations
 type UploadService interface {
-	// UploadFile uploads a file to Supabase Storage and returns the public URL
-	UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*web.UploadResult, error)
+	// UploadFile stores a file in the object store and records it as an attachment of the uploader
+	UploadFile(ctx context.Context, userID string, file multipart.File, header *multipart.FileHeader, req *web.UploadFileRequest) (*web.UploadResult, error)
+
+	// GetMedia checks that the user can see an attachment and returns a short-lived signed download URL
+	// of the original or of one of its thumbnails
+	GetMedia(ctx context.Context, userID, attachmentID string, req *web.GetMediaRequest) (*web.MediaResponse, error)
+
+	// CreateResumableUpload starts a resumable (tus) upload of a file of the given length
+	CreateResumableUpload(ctx context.Context, userID string, req *web.CreateResumableUploadRequest) (*web.ResumableUploadResponse, error)
+
+	// GetResumableUpload returns the state of one of the user's resumable uploads (with its attachment once complete)
+	GetResumableUpload(ctx context.Context, userID, uploadID string) (*web.ResumableUploadResponse, error)
+
+	// WriteResumableChunk appends a chunk at the current offset of a resumable upload.
+	// The last chunk stores the file like UploadFile and creates the attachment
+	WriteResumableChunk(ctx context.Context, userID, uploadID string, req *web.PatchResumableUploadRequest, chunk io.Reader) (*web.ResumableUploadResponse, error)
+
+	// DeleteResumableUpload cancels one of the user's resumable uploads and removes the bytes received
+	DeleteResumableUpload(ctx context.Context, userID, uploadID string) error
+
+	// GetStorageUsage returns the user's stored bytes and uploads in the last hour against their limits
+	GetStorageUsage(ctx context.Context, userID string) (*web.StorageUsageResponse, error)
+
+	// RunResumableCleanup periodically removes expired resumable uploads (blocking, run in a goroutine)
+	RunResumableCleanup()
+
+	// CollectGarbage removes attachments nothing used for the grace period and deletes their files when no other
+	// attachment shares them. With dryRun it only logs what would be removed
+	CollectGarbage(ctx context.Context, dryRun bool) (*GarbageCollectionResult, error)
+
+	// RunGarbageCollection periodically collects orphaned uploads (blocking, run in a goroutine)
+	RunGarbageCollection()
 }
\ No newline at end of file
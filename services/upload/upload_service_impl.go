@@ -1,18 +1,35 @@
 package upload
 
 import (
+	"bytes"
+	"chatapp-api/apps/scanner"
+	"chatapp-api/apps/storage"
 	"chatapp-api/config"
 	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
 	"chatapp-api/models/web"
+	attachmentRepo "chatapp-api/repositories/attachment"
+	conversationRepo "chatapp-api/repositories/conversation"
+	messageRepo "chatapp-api/repositories/message"
+	resumableUploadRepo "chatapp-api/repositories/resumable_upload"
+	storedObjectRepo "chatapp-api/repositories/stored_object"
+	uploadReservationRepo "chatapp-api/repositories/upload_reservation"
+	userRepo "chatapp-api/repositories/user"
+	"chatapp-api/utils"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
-	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
 )
 
 // Allowed MIME types
@@ -38,84 +55,454 @@ var allowedMimeTypes = map[string]bool{
 
 const maxFileSize = 20 * 1024 * 1024
 
+// uploadRateWindow is the rolling window of the per-user upload count limit
+const uploadRateWindow = time.Hour
+
+// uploadReservationTTL is how long an upload counts towards the limits without its attachment being recorded
+// (longer than scanning and storing the largest file, then the upload is assumed to have crashed)
+const uploadReservationTTL = time.Hour
+
 // uploadServiceImpl implements the UploadService interface
 type uploadServiceImpl struct {
+	objectStore storage.ObjectStore
+	scanner scanner.Scanner
+	attachmentRepo attachmentRepo.AttachmentRepository
+	storedObjectRepo storedObjectRepo.StoredObjectRepository
+	resumableUploadRepo resumableUploadRepo.ResumableUploadRepository
+	uploadReservationRepo uploadReservationRepo.UploadReservationRepository
+	messageRepo messageRepo.MessageRepository
+	convRepo conversationRepo.ConversationRepository
+	userRepo userRepo.UserRepository
 	config *config.Config
 }
 
 // NewUploadService creates a new instance of UploadService
-func NewUploadService(config *config.Config) UploadService {
-	return &uploadServiceImpl{config: config}
+func NewUploadService(objectStore storage.ObjectStore, scanner scanner.Scanner, attachmentRepo attachmentRepo.AttachmentRepository, storedObjectRepo storedObjectRepo.StoredObjectRepository, resumableUploadRepo resumableUploadRepo.ResumableUploadRepository, uploadReservationRepo uploadReservationRepo.UploadReservationRepository, messageRepo messageRepo.MessageRepository, convRepo conversationRepo.ConversationRepository, userRepo userRepo.UserRepository, config *config.Config) UploadService {
+	return &uploadServiceImpl{
+		objectStore: objectStore,
+		scanner: scanner,
+		attachmentRepo: attachmentRepo,
+		storedObjectRepo: storedObjectRepo,
+		resumableUploadRepo: resumableUploadRepo,
+		uploadReservationRepo: uploadReservationRepo,
+		messageRepo: messageRepo,
+		convRepo: convRepo,
+		userRepo: userRepo,
+		config: config,
+	}
 }
 
 
-// UploadFile uploads a file to Supabase Storage and returns the public URL
-func (service *uploadServiceImpl) UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*web.UploadResult, error) {
+// UploadFile stores a file in the object store and records it as an attachment of the uploader
+func (service *uploadServiceImpl) UploadFile(ctx context.Context, userID string, file multipart.File, header *multipart.FileHeader, req *web.UploadFileRequest) (*web.UploadResult, error) {
 	// 1. Validate file size
 	if header.Size > maxFileSize {
         return nil, exceptions.NewBadRequestError(
             fmt.Sprintf("File too large. Maximum size is %d MB", maxFileSize/(1024*1024)))
     }
 
-	// 2. Detect MIME type from file content
+	// 2. Reserve room in the user's storage quota and upload rate until the attachment is recorded
+	reservationID, err := service.reserveUpload(ctx, userID, header.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer service.releaseUpload(ctx, reservationID)
+
+	// 3. Detect and validate the MIME type from the file content
+	mimetype, err := detectMimeType(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Scan it for malware before anything is stored
+	scanStatus, err := service.scanFile(ctx, userID, header.Filename, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Store the file and record the attachment
+	attachment, err := service.storeAttachment(ctx, userID, file, header.Filename, header.Size, mimetype, scanStatus, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Return the attachment (downloads go through GET /media/:id)
+	return toUploadResult(attachment), nil
+}
+
+// checkUploadLimits rejects an upload of the given size that would exceed the user's storage quota (413)
+// or their number of uploads in the last hour (429)
+func (service *uploadServiceImpl) checkUploadLimits(ctx context.Context, userID string, size int64) error {
+	// 1. Storage quota: total size of the user's attachments
+	if service.config.Storage.QuotaMB > 0 {
+		used, err := service.attachmentRepo.SumSizeByUploader(ctx, userID)
+		if err != nil {
+			return exceptions.NewInternalServerError("Failed to check storage quota")
+		}
+
+		quota := int64(service.config.Storage.QuotaMB) * 1024 * 1024
+		if used+size > quota {
+			return service.quotaExceededError(used)
+		}
+	}
+
+	// 2. Upload rate: uploads in the last hour
+	if service.config.Storage.UploadsPerHour > 0 {
+		count, err := service.attachmentRepo.CountByUploaderSince(ctx, userID, time.Now().Add(-uploadRateWindow))
+		if err != nil {
+			return exceptions.NewInternalServerError("Failed to check upload rate")
+		}
+
+		if count >= int64(service.config.Storage.UploadsPerHour) {
+			return service.rateExceededError()
+		}
+	}
+
+	return nil
+}
+
+// reserveUpload checks the user's limits like checkUploadLimits and counts the upload towards them until
+// releaseUpload, so concurrent uploads can't exceed them together. Returns the reservation ID
+func (service *uploadServiceImpl) reserveUpload(ctx context.Context, userID string, size int64) (string, error) {
+	reservation := &domain.UploadReservation{UserID: userID, Size: size}
+	limits := uploadReservationRepo.UploadLimits{
+		QuotaBytes: int64(service.config.Storage.QuotaMB) * 1024 * 1024,
+		UploadsPerWindow: int64(service.config.Storage.UploadsPerHour),
+		Window: uploadRateWindow,
+	}
+
+	err := service.uploadReservationRepo.Reserve(ctx, reservation, limits, uploadReservationTTL)
+	if errors.Is(err, uploadReservationRepo.ErrQuotaExceeded) {
+		used, err := service.attachmentRepo.SumSizeByUploader(ctx, userID)
+		if err != nil {
+			return "", exceptions.NewInternalServerError("Failed to check storage quota")
+		}
+		return "", service.quotaExceededError(used)
+	}
+	if errors.Is(err, uploadReservationRepo.ErrRateExceeded) {
+		return "", service.rateExceededError()
+	}
+	if err != nil {
+		return "", exceptions.NewInternalServerError("Failed to check upload limits")
+	}
+
+	return reservation.ID, nil
+}
+
+// releaseUpload stops counting a reserved upload (its attachment, if any, is counted instead).
+// Runs even if the request was cancelled, a reservation left behind counts until it goes stale
+func (service *uploadServiceImpl) releaseUpload(ctx context.Context, reservationID string) {
+	if err := service.uploadReservationRepo.Release(context.WithoutCancel(ctx), reservationID); err != nil {
+		log.Printf("Failed to release upload reservation %s: %v", reservationID, err)
+	}
+}
+
+// quotaExceededError is the 413 returned when an upload doesn't fit in the user's storage quota
+func (service *uploadServiceImpl) quotaExceededError(used int64) error {
+	return exceptions.NewPayloadTooLargeError(
+		fmt.Sprintf("Storage quota exceeded: %d MB used of %d MB", used/(1024*1024), service.config.Storage.QuotaMB))
+}
+
+// rateExceededError is the 429 returned when the user reached their number of uploads per hour
+func (service *uploadServiceImpl) rateExceededError() error {
+	return exceptions.NewTooManyRequestsError(
+		fmt.Sprintf("Upload limit reached: %d uploads per hour, try again later", service.config.Storage.UploadsPerHour))
+}
+
+// GetStorageUsage returns how much of their storage quota and hourly upload limit the user has used
+func (service *uploadServiceImpl) GetStorageUsage(ctx context.Context, userID string) (*web.StorageUsageResponse, error) {
+	used, err := service.attachmentRepo.SumSizeByUploader(ctx, userID)
+	if err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to get storage usage")
+	}
+
+	uploadsLastHour, err := service.attachmentRepo.CountByUploaderSince(ctx, userID, time.Now().Add(-uploadRateWindow))
+	if err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to get storage usage")
+	}
+
+	usage := &web.StorageUsageResponse{
+		UsedBytes: used,
+		UploadsLastHour: uploadsLastHour,
+	}
+	if service.config.Storage.QuotaMB > 0 {
+		quota := int64(service.config.Storage.QuotaMB) * 1024 * 1024
+		remaining := max(0, quota-used)
+		usage.QuotaBytes = &quota
+		usage.RemainingBytes = &remaining
+	}
+	if service.config.Storage.UploadsPerHour > 0 {
+		uploadsPerHour := service.config.Storage.UploadsPerHour
+		usage.UploadsPerHour = &uploadsPerHour
+	}
+	return usage, nil
+}
+
+// detectMimeType sniffs the MIME type from the first bytes of a file and checks it is allowed
+func detectMimeType(file io.ReadSeeker) (string, error) {
+	// 1. Detect MIME type from file content
 	buffer := make([]byte, 512)
 	_, err := file.Read(buffer)
 	if err != nil {
-		return nil, exceptions.NewInternalServerError("Failed to read file")
+		return "", exceptions.NewInternalServerError("Failed to read file")
 	}
 	mimetype := http.DetectContentType(buffer)
 	file.Seek(0, io.SeekStart)
 
-	// 3. Validate MIME type
+	// 2. Validate MIME type
 	if !allowedMimeTypes[mimetype] {
-		return nil, exceptions.NewBadRequestError(
+		return "", exceptions.NewBadRequestError(
 			fmt.Sprintf("File type '%s' is not allowed", mimetype))
 	}
-	
-	// 4. Generate unique filename
-	extension := filepath.Ext(header.Filename)
-	if extension == "" {
-		extension = getExtensionFromMime(mimetype)
+	return mimetype, nil
+}
+
+// storeAttachment stores a validated and scanned file (original, thumbnails) through the object store and records it as an attachment.
+// A file with the same content as one already stored isn't stored again, the attachment references it.
+// Shared by direct and resumable uploads
+func (service *uploadServiceImpl) storeAttachment(ctx context.Context, userID string, file io.ReadSeeker, originalName string, size int64, mimetype string, scanStatus string, req *web.UploadFileRequest) (*domain.Attachment, error) {
+	// 1. Images are processed in memory: remove location and camera metadata unless the client
+	// sends the file as is ("send as file"), the stored size is then the sanitized one
+	var content io.ReadSeeker = file
+	orientation := 1
+	if strings.HasPrefix(mimetype, "image/") {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, exceptions.NewInternalServerError("Failed to read file")
+		}
+		orientation = imageOrientation(mimetype, data)
+		if !req.KeepMetadata {
+			data = stripImageMetadata(mimetype, data, orientation)
+		}
+		content = bytes.NewReader(data)
+		size = int64(len(data))
+	}
+
+	// 2. Hash the content: identical files share one object in the store
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to read file")
+	}
+	content.Seek(0, io.SeekStart)
+	sha := hex.EncodeToString(hash.Sum(nil))
+
+	// 3. Generate the attachment ID. The object key comes from the content: subfolder based on MIME type, then the hash
+	filename := originalName
+	if utf8.RuneCountInString(filename) > 255 {
+		filename = string([]rune(filename)[:255])
+	}
+	attachment := &domain.Attachment{
+		ID: utils.GenerateID("att"),
+		UploaderID: userID,
+		ObjectKey: getSubfolder(mimetype) + "/" + sha + getExtensionFromMime(mimetype),
+		Filename: filename,
+		MimeType: mimetype,
+		Size: size,
+		SHA256: sha,
+	}
+	service.recordScan(attachment, scanStatus)
+
+	// 4. Metadata: image dimensions, thumbnails and BlurHash, duration reported by the client for audio/video
+	var processed *processedImage
+	if strings.HasPrefix(mimetype, "image/") {
+		processed = processImage(content, orientation)
+	}
+	if processed != nil {
+		attachment.Width = &processed.width
+		attachment.Height = &processed.height
+		if processed.blurhash != "" {
+			attachment.Blurhash = &processed.blurhash
+		}
+	}
+	if req.DurationMs != nil && (strings.HasPrefix(mimetype, "audio/") || strings.HasPrefix(mimetype, "video/")) {
+		attachment.DurationMs = req.DurationMs
+	}
+
+	// 5. Reference the object; the file is stored unless an earlier upload already stored it
+	object := &domain.StoredObject{
+		ObjectKey: attachment.ObjectKey,
+		SHA256: sha,
+		MimeType: mimetype,
+		Size: size,
+	}
+	if _, err := service.storedObjectRepo.Acquire(ctx, object); err != nil {
+		return nil, exceptions.NewInternalServerError("Failed to save attachment")
+	}
+
+	if object.Ready {
+		// 5a. Same content already stored: reuse the file and its thumbnails
+		attachment.Thumbnails = object.Thumbnails
+	} else {
+		// 5b. Not stored yet (new file, or an upload of the same file still storing it or failed): store it through
+		// the configured object store, then its thumbnails (best effort, clients fall back to the original).
+		// The key comes from the content, an upload of the same file storing it meanwhile writes the same bytes
+		err := service.objectStore.Put(ctx, attachment.ObjectKey, content, size, mimetype)
+		if err != nil {
+			log.Printf("Failed to store upload %s: %v", attachment.ObjectKey, err)
+			service.releaseObject(ctx, attachment.ObjectKey)
+			return nil, exceptions.NewInternalServerError("Failed to upload file to storage")
+		}
+		if processed != nil {
+			attachment.Thumbnails = service.storeThumbnails(ctx, attachment.ObjectKey, processed.thumbnails)
+		}
+
+		// Later uploads of the same file reuse it; if this fails they store it again
+		if err := service.storedObjectRepo.MarkReady(ctx, attachment.ObjectKey, attachment.Thumbnails); err != nil {
+			log.Printf("Failed to mark upload %s as stored: %v", attachment.ObjectKey, err)
+		}
+	}
+
+	// 6. Record the attachment (the reference is useless without it)
+	if err := service.attachmentRepo.Create(ctx, attachment); err != nil {
+		service.releaseObject(ctx, attachment.ObjectKey)
+		return nil, exceptions.NewInternalServerError("Failed to save attachment")
+	}
+
+	return attachment, nil
+}
+
+// toUploadResult converts an attachment to its upload result DTO
+func toUploadResult(attachment *domain.Attachment) *web.UploadResult {
+	return &web.UploadResult{
+		ID: attachment.ID,
+		URL: attachment.URL,
+		Filename: attachment.Filename,
+		Size: attachment.Size,
+		MimeType: attachment.MimeType,
+		Width: attachment.Width,
+		Height: attachment.Height,
+		DurationMs: attachment.DurationMs,
+		Thumbnails: toThumbnailResponses(attachment.Thumbnails),
+		Blurhash: attachment.Blurhash,
+		SHA256: attachment.SHA256,
+		ScanStatus: attachment.ScanStatus,
 	}
-	uniqueFileName := ulid.Make().String() + extension
+}
+
+// storeThumbnails stores generated thumbnails of a stored file, skipping the ones that fail
+func (service *uploadServiceImpl) storeThumbnails(ctx context.Context, objectKey string, thumbnails []encodedThumbnail) domain.AttachmentThumbnails {
+	var stored domain.AttachmentThumbnails
+	for _, thumbnail := range thumbnails {
+		key := domain.ThumbnailObjectKey(objectKey, thumbnail.name)
+		err := service.objectStore.Put(ctx, key, bytes.NewReader(thumbnail.data), int64(len(thumbnail.data)), "image/jpeg")
+		if err != nil {
+			log.Printf("Failed to store thumbnail %s: %v", key, err)
+			continue
+		}
+		stored = append(stored, domain.AttachmentThumbnail{
+			Name: thumbnail.name,
+			Width: thumbnail.width,
+			Height: thumbnail.height,
+		})
+	}
+	return stored
+}
+
+// releaseObject drops an attachment's reference to a stored file. The last reference deletes the file
+// and its thumbnails from the object store
+func (service *uploadServiceImpl) releaseObject(ctx context.Context, objectKey string) {
+	err := service.storedObjectRepo.Release(ctx, objectKey, func() error {
+		return service.deleteStoredFiles(ctx, objectKey)
+	})
+	if err != nil {
+		log.Printf("Failed to release stored file %s: %v", objectKey, err)
+	}
+}
+
+// deleteStoredFiles deletes a stored file and every thumbnail size, whichever were generated
+func (service *uploadServiceImpl) deleteStoredFiles(ctx context.Context, objectKey string) error {
+	keys := []string{objectKey}
+	for _, size := range thumbnailSizes {
+		keys = append(keys, domain.ThumbnailObjectKey(objectKey, size.name))
+	}
+
+	for _, key := range keys {
+		if err := service.objectStore.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// 5. Subfolder based on MIME type
-	subfolder := getSubfolder(mimetype)
-	objectPath := subfolder + "/" + uniqueFileName
+// toThumbnailResponses converts thumbnails to their response DTOs
+func toThumbnailResponses(thumbnails domain.AttachmentThumbnails) []web.ThumbnailResponse {
+	var responses []web.ThumbnailResponse
+	for _, thumbnail := range thumbnails {
+		responses = append(responses, web.ThumbnailResponse{
+			Name: thumbnail.Name,
+			Width: thumbnail.Width,
+			Height: thumbnail.Height,
+			URL: thumbnail.URL,
+		})
+	}
+	return responses
+}
 
-	// 6. Upload to Supabase Storage via HTTP POST
-	uploadURL := fmt.Sprintf("%s/storage/v1/object/%s/%s", service.config.Supabase.URL, service.config.Supabase.Bucket, objectPath)
+// GetMedia checks that the user can see an attachment and returns a short-lived signed download URL
+func (service *uploadServiceImpl) GetMedia(ctx context.Context, userID, attachmentID string, req *web.GetMediaRequest) (*web.MediaResponse, error) {
+	// 1. Find the attachment
+	attachment, err := service.attachmentRepo.FindByID(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("Media not found")
+		}
+		return nil, err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, file)
+	// 2. Check access
+	canAccess, err := service.canAccessMedia(ctx, userID, attachment)
 	if err != nil {
-		return nil, exceptions.NewInternalServerError("Failed to create upload request")
+		return nil, exceptions.NewInternalServerError("Failed to check media access")
+	}
+	if !canAccess {
+		return nil, exceptions.NewForbiddenError("You don't have access to this media")
+	}
+
+	// 3. Pick the original or one of its thumbnails
+	objectKey := attachment.ObjectKey
+	if req.Thumbnail != "" {
+		if attachment.FindThumbnail(req.Thumbnail) == nil {
+			return nil, exceptions.NewNotFoundError("Thumbnail not found")
+		}
+		objectKey = domain.ThumbnailObjectKey(attachment.ObjectKey, req.Thumbnail)
 	}
-	req.Header.Set("Authorization", "Bearer "+service.config.Supabase.Key)
-	req.Header.Set("Content-Type", mimetype)
 
-	client := &http.Client{}
-	response, err := client.Do(req)
+	// 4. Sign a download URL
+	expiry := time.Duration(service.config.Storage.SignedURLExpiryMins) * time.Minute
+	signedURL, err := service.objectStore.SignedURL(ctx, objectKey, expiry)
 	if err != nil {
-		return nil, exceptions.NewInternalServerError("Failed to upload file to storage")
+		log.Printf("Failed to sign media URL %s: %v", attachment.ID, err)
+		return nil, exceptions.NewInternalServerError("Failed to create media URL")
 	}
-	defer response.Body.Close()
 
-	// 7. Check the response
-	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(response.Body)
-		return nil, exceptions.NewInternalServerError(
-			fmt.Sprintf("Storage upload failed (status %d): %s", response.StatusCode, string(body)))
+	return &web.MediaResponse{
+		ID: attachment.ID,
+		URL: signedURL,
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+// canAccessMedia reports whether an attachment is visible to the user
+func (service *uploadServiceImpl) canAccessMedia(ctx context.Context, userID string, attachment *domain.Attachment) (bool, error) {
+	// 1. The uploader (e.g. preview before sending)
+	if attachment.UploaderID == userID {
+		return true, nil
+	}
+
+	// 2. Sent with a message in a conversation the user participates in
+	visible, err := service.attachmentRepo.IsVisibleToUser(ctx, attachment.ID, userID)
+	if err != nil || visible {
+		return visible, err
 	}
 
-	// 8. Return public URL
-	   publicURL := fmt.Sprintf("%s/storage/v1/object/public/%s/%s", service.config.Supabase.URL, service.config.Supabase.Bucket, objectPath)
+	// 3. Picture of a group the user participates in (set by attachment ID, checked to be the setter's own upload)
+	isConvAvatar, err := service.convRepo.IsAvatarVisibleToUser(ctx, attachment.ID, userID)
+	if err != nil || isConvAvatar {
+		return isConvAvatar, err
+	}
 
-	   return &web.UploadResult{
-		URL: publicURL,
-		Filename: header.Filename,
-		Size: header.Size,
-		MimeType: mimetype,
-	   }, nil
+	// 4. Profile picture (profiles are visible to every user)
+	return service.userRepo.ExistsByAvatar(ctx, attachment.ID)
 }
 
 // getSubfoler helper method to determine folder based on MIME prefix
@@ -146,6 +533,8 @@ func getExtensionFromMime(mimeType string) string {
         "audio/wav":  ".wav",
         "audio/webm": ".webm",
         "application/pdf": ".pdf",
+        "application/msword": ".doc",
+        "application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
     }
     if ext, ok := extensions[mimeType]; ok {
         return ext
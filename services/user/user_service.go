@@ -0,0 +1,30 @@
+package user
+
+import (
+	"chatapp-api/models/web"
+	"context"
+)
+
+// UserService interface for user features (directory, blocking, presence)
+type UserService interface {
+	// SearchUsers searches the user directory by name, username or email (excludes yourself and blocked users)
+	SearchUsers(ctx context.Context, userID, query string, page, limit int) ([]web.UserBriefResponse, *web.PaginationMeta, error)
+
+	// GetPublicProfile gets another user's public profile (filtered by their privacy settings)
+	GetPublicProfile(ctx context.Context, userID, targetUserID string) (*web.PublicProfileResponse, error)
+
+	// GetProfileByUsername gets another user's public profile by their username (case-insensitive)
+	GetProfileByUsername(ctx context.Context, userID, username string) (*web.PublicProfileResponse, error)
+
+	// BlockUser blocks a user (no DMs, typing or presence between the two users) and removes them from contacts
+	BlockUser(ctx context.Context, userID, targetUserID string) error
+
+	// UnblockUser removes a block
+	UnblockUser(ctx context.Context, userID, targetUserID string) error
+
+	// GetBlockedUsers lists users blocked by the current user
+	GetBlockedUsers(ctx context.Context, userID string) ([]web.BlockedUserResponse, error)
+
+	// UpdatePresence sets DND and the custom status of the current user
+	UpdatePresence(ctx context.Context, userID string, req *web.UpdatePresenceRequest) (*web.PresenceResponse, error)
+}
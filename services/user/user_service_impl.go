@@ -0,0 +1,315 @@
+package user
+
+import (
+	"chatapp-api/exceptions"
+	"chatapp-api/models/domain"
+	"chatapp-api/models/web"
+	contactRepo "chatapp-api/repositories/contact"
+	presenceRepo "chatapp-api/repositories/presence"
+	userRepo "chatapp-api/repositories/user"
+	userBlockRepo "chatapp-api/repositories/user_block"
+	"chatapp-api/websocket"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// userServiceImpl implements UserService interface
+type userServiceImpl struct {
+	userRepo      userRepo.UserRepository
+	contactRepo   contactRepo.ContactRepository
+	presenceRepo  presenceRepo.PresenceRepository
+	userBlockRepo userBlockRepo.UserBlockRepository
+	hub           *websocket.Hub
+}
+
+// NewUserService creates a new UserService instance
+func NewUserService(
+	userRepo userRepo.UserRepository,
+	contactRepo contactRepo.ContactRepository,
+	presenceRepo presenceRepo.PresenceRepository,
+	userBlockRepo userBlockRepo.UserBlockRepository,
+	hub *websocket.Hub) UserService {
+	return &userServiceImpl{
+		userRepo:      userRepo,
+		contactRepo:   contactRepo,
+		presenceRepo:  presenceRepo,
+		userBlockRepo: userBlockRepo,
+		hub:           hub,
+	}
+}
+
+// minSearchQueryLength is the shortest search query (shorter ones match too many users)
+const minSearchQueryLength = 2
+
+// SearchUsers implements UserService
+func (service *userServiceImpl) SearchUsers(ctx context.Context, userID, query string, page, limit int) ([]web.UserBriefResponse, *web.PaginationMeta, error) {
+	// 1. Validate query
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minSearchQueryLength {
+		return nil, nil, exceptions.NewBadRequestError("Search query must be at least 2 characters")
+	}
+
+	// 2. Set default pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// 3. Exclude yourself and users blocked either way
+	excludeIDs, err := service.userBlockRepo.FindRelatedUserIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	excludeIDs = append(excludeIDs, userID)
+
+	// 4. Search
+	users, total, err := service.userRepo.Search(ctx, query, excludeIDs, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 5. Load live presence and the user's contacts (for presence visibility)
+	contactIDs, err := service.findContactIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userPtrs := make([]*domain.User, len(users))
+	for idx := range users {
+		userPtrs[idx] = &users[idx]
+	}
+	if err := service.presenceRepo.Load(ctx, userPtrs...); err != nil {
+		return nil, nil, err
+	}
+
+	// 6. Convert to response
+	result := make([]web.UserBriefResponse, len(users))
+	for idx := range users {
+		result[idx] = web.NewUserBrief(&users[idx], userID, contactIDs[users[idx].ID])
+	}
+
+	pagination := &web.PaginationMeta{
+		CurrentPage: page,
+		PerPage: limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+
+	return result, pagination, nil
+}
+
+// GetPublicProfile implements UserService
+func (service *userServiceImpl) GetPublicProfile(ctx context.Context, userID, targetUserID string) (*web.PublicProfileResponse, error) {
+	// 1. Find user
+	user, err := service.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("User not found")
+		}
+		return nil, err
+	}
+
+	// 2. Build profile
+	return service.buildPublicProfile(ctx, userID, user)
+}
+
+// GetProfileByUsername implements UserService
+func (service *userServiceImpl) GetProfileByUsername(ctx context.Context, userID, username string) (*web.PublicProfileResponse, error) {
+	// 1. Find user by handle ("@alice" and "alice" both work)
+	user, err := service.userRepo.FindByUsername(ctx, strings.TrimPrefix(username, "@"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("User not found")
+		}
+		return nil, err
+	}
+
+	// 2. Build profile
+	return service.buildPublicProfile(ctx, userID, user)
+}
+
+// buildPublicProfile builds the profile of user as seen by userID (filtered by blocks and privacy settings)
+func (service *userServiceImpl) buildPublicProfile(ctx context.Context, userID string, user *domain.User) (*web.PublicProfileResponse, error) {
+	// 1. Users who blocked you are hidden (same as not existing)
+	blockedMe, err := service.userBlockRepo.HasBlocked(ctx, user.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blockedMe {
+		return nil, exceptions.NewNotFoundError("User not found")
+	}
+
+	blockedByMe, err := service.userBlockRepo.HasBlocked(ctx, userID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Build public profile (no email)
+	profile := &web.PublicProfileResponse{
+		ID: user.ID,
+		Name: user.Name,
+		Username: user.Username,
+		AvatarURL: user.AvatarURL,
+		IsBlocked: blockedByMe,
+		CreatedAt: user.CreatedAt,
+	}
+
+	// 3. Presence only if the user shares it with you (never between blocked users)
+	contactIDs, err := service.findContactIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !blockedByMe && user.CanSeePresence(userID, contactIDs[user.ID]) {
+		if err := service.presenceRepo.Load(ctx, user); err != nil {
+			return nil, err
+		}
+
+		profile.IsOnline = user.IsOnline
+		profile.Status = user.PresenceStatus
+		profile.StatusText, profile.StatusEmoji = user.ActiveCustomStatus(time.Now())
+		if !user.IsOnline {
+			profile.LastSeen = user.LastSeen
+		}
+	}
+
+	return profile, nil
+}
+
+// BlockUser implements UserService
+func (service *userServiceImpl) BlockUser(ctx context.Context, userID, targetUserID string) error {
+	// 1. Validation: Can't block yourself
+	if userID == targetUserID {
+		return exceptions.NewBadRequestError("You can't block yourself")
+	}
+
+	// 2. Validation: Target user must exist
+	if _, err := service.userRepo.FindByID(ctx, targetUserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.NewNotFoundError("User not found")
+		}
+		return err
+	}
+
+	// 3. Save block (blocking twice is a no-op)
+	if err := service.userBlockRepo.Create(ctx, &domain.UserBlock{
+		BlockerID: userID,
+		BlockedID: targetUserID,
+	}); err != nil {
+		return err
+	}
+
+	// 4. Blocking ends the contact (and cancels pending requests) in both directions
+	return service.contactRepo.DeleteBetween(ctx, userID, targetUserID)
+}
+
+// UnblockUser implements UserService
+func (service *userServiceImpl) UnblockUser(ctx context.Context, userID, targetUserID string) error {
+	deleted, err := service.userBlockRepo.Delete(ctx, userID, targetUserID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return exceptions.NewNotFoundError("User is not blocked")
+	}
+	return nil
+}
+
+// GetBlockedUsers implements UserService
+func (service *userServiceImpl) GetBlockedUsers(ctx context.Context, userID string) ([]web.BlockedUserResponse, error) {
+	// 1. Get blocks made by the user
+	blocks, err := service.userBlockRepo.FindByBlockerID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Convert to response (online status is hidden between blocked users)
+	result := make([]web.BlockedUserResponse, len(blocks))
+	for idx, block := range blocks {
+		result[idx] = web.BlockedUserResponse{
+			User: web.UserBriefResponse{
+				ID: block.Blocked.ID,
+				Name: block.Blocked.Name,
+				Username: block.Blocked.Username,
+				AvatarURL: block.Blocked.AvatarURL,
+			},
+			BlockedAt: block.CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// UpdatePresence implements UserService
+func (service *userServiceImpl) UpdatePresence(ctx context.Context, userID string, req *web.UpdatePresenceRequest) (*web.PresenceResponse, error) {
+	// 1. Validate update
+	update := &domain.PresenceUpdate{
+		Status:           req.Status,
+		StatusText:       req.StatusText,
+		StatusEmoji:      req.StatusEmoji,
+		ExpiresInMinutes: req.ExpiresInMinutes,
+		ClearStatus:      req.ClearStatus,
+	}
+	if err := update.Validate(); err != nil {
+		return nil, exceptions.NewBadRequestError(err.Error())
+	}
+
+	// 2. Find user
+	user, err := service.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewNotFoundError("User not found")
+		}
+		return nil, err
+	}
+
+	// 3. Apply and save settings
+	user.ApplyPresenceUpdate(update, time.Now())
+	if err := service.userRepo.UpdatePresenceSettings(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// 4. Recompute presence state (e.g. online -> dnd) and tell peers
+	if service.hub != nil {
+		service.hub.RefreshPresence(userID)
+	}
+
+	if err := service.presenceRepo.Load(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// 5. Return response
+	statusText, statusEmoji := user.ActiveCustomStatus(time.Now())
+	response := &web.PresenceResponse{
+		Status:       user.PresenceStatus,
+		DoNotDisturb: user.DoNotDisturb,
+		StatusText:   statusText,
+		StatusEmoji:  statusEmoji,
+	}
+	if statusText != nil || statusEmoji != nil {
+		response.StatusExpiresAt = user.StatusExpiresAt
+	}
+
+	return response, nil
+}
+
+// findContactIDs returns the accepted contacts of userID
+func (service *userServiceImpl) findContactIDs(ctx context.Context, userID string) (map[string]bool, error) {
+	ids, err := service.contactRepo.FindContactIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	contactIDs := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		contactIDs[id] = true
+	}
+	return contactIDs, nil
+}
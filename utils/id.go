@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"math/big"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -12,4 +13,21 @@ func GenerateID(prefix string) string {
 	entropy := ulid.Monotonic(rand.Reader, 0)
 	id := ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
 	return prefix + "_" + id.String()
-}
\ No newline at end of file
+}
+
+// codeAlphabet is used for human-shareable codes (no ambiguous characters like 0/O, 1/l)
+const codeAlphabet = "23456789abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// GenerateCode generates a random shareable code (e.g. for invite links)
+func GenerateCode(length int) string {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for idx := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		code[idx] = codeAlphabet[n.Int64()]
+	}
+	return string(code)
+}
@@ -3,6 +3,7 @@ package websocket
 import (
 	"encoding/json"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,11 +16,17 @@ const (
 	// Send ping interval to client (must < pongWait)
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size (bytes)
-	maxMessageSize = 512
+	// Maximum message size (bytes), fits a presence_update with a full custom status
+	maxMessageSize = 1024
 
 	// Buffer size for send channel
 	sendBufferSize = 256
+
+	// User becomes idle after this long without any event from the client
+	idleTimeout = 5 * time.Minute
+
+	// Presence in Redis expires after this long without a pong (heartbeat)
+	presenceTTL = pongWait + 30*time.Second
 )
 
 // Client represents a WebSocket connection
@@ -28,6 +35,11 @@ type Client struct {
 	conn *websocket.Conn
 	userID string
 	send chan []byte
+
+	// Activity tracking for idle presence (read by WritePump, written by ReadPump)
+	activityMu sync.Mutex
+	lastActivity time.Time
+	idle bool
 }
 
 // NewClient creates a new Client instance
@@ -37,7 +49,41 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID string) *Client {
 		conn: conn,
 		userID: userID,
 		send: make(chan []byte, sendBufferSize),
+		lastActivity: time.Now(),
+	}
+}
+
+// touch records activity from the client
+// Returns true if the client was idle (so presence goes back to online)
+func (client *Client) touch() bool {
+	client.activityMu.Lock()
+	defer client.activityMu.Unlock()
+
+	wasIdle := client.idle
+	client.lastActivity = time.Now()
+	client.idle = false
+	return wasIdle
+}
+
+// checkIdle marks the client idle after idleTimeout without activity
+// Returns true only when the client just became idle
+func (client *Client) checkIdle() bool {
+	client.activityMu.Lock()
+	defer client.activityMu.Unlock()
+
+	if client.idle || time.Since(client.lastActivity) < idleTimeout {
+		return false
 	}
+	client.idle = true
+	return true
+}
+
+// isIdle checks if the client is idle
+func (client *Client) isIdle() bool {
+	client.activityMu.Lock()
+	defer client.activityMu.Unlock()
+
+	return client.idle
 }
 
 // ReadPum reads messages from the WebSocket connection
@@ -52,6 +98,7 @@ func (client *Client) ReadPump() {
 	client.conn.SetReadDeadline(time.Now().Add(pongWait))
 	client.conn.SetPongHandler(func(string) error {
 		client.conn.SetReadDeadline(time.Now().Add(pongWait))
+		client.hub.heartbeat(client.userID)
 		return nil
 	})
 
@@ -74,6 +121,11 @@ func (client *Client) ReadPump() {
 			log.Printf("Invalid message format from user %s: %v", client.userID, err)
 			continue // Skip message with incorrect formatting, continue reading the next message
 		}
+
+		// Any event from the client counts as activity (idle -> online)
+		if client.touch() {
+			client.hub.RefreshPresence(client.userID)
+		}
 		
 		// Handle based on event type
 		switch wsMessage.Event{
@@ -108,6 +160,16 @@ func (client *Client) ReadPump() {
 		}
 		client.hub.HandleMessageDeliveredEvent(client.userID, wsMessage.ConversationID, messageID)
 
+		case EventPresenceUpdate:
+		// Client sends: {"event":"presence_update", "data":{"status":"dnd", "status_text":"In a meeting", "status_emoji":"📅", "expires_in_minutes":60}}
+		var data PresenceUpdateData
+		raw, err := json.Marshal(wsMessage.Data)
+		if err != nil || json.Unmarshal(raw, &data) != nil {
+			log.Printf("Invalid presence_update data from user %s", client.userID)
+			break
+		}
+		client.hub.HandlePresenceUpdateEvent(client.userID, data)
+
 		default:
 			log.Printf("Unknown event from user %s: %s", client.userID, wsMessage.Event)
 		}
@@ -148,6 +210,11 @@ func (client *Client) WritePump() {
 			err != nil {
 				return
 			}
+
+			// No events from the client for a while: online -> idle
+			if client.checkIdle() {
+				go client.hub.RefreshPresence(client.userID)
+			}
 		}
 	}
 }
\ No newline at end of file
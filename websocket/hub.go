@@ -5,12 +5,20 @@ import (
 	"encoding/json"
 	"log"
 	"sync"
+	"time"
 
+	"chatapp-api/models/domain"
+	contactRepo "chatapp-api/repositories/contact"
 	conversationRepo "chatapp-api/repositories/conversation"
 	receiptRepo "chatapp-api/repositories/message_receipt"
+	presenceRepo "chatapp-api/repositories/presence"
 	userRepo "chatapp-api/repositories/user"
+	userBlockRepo "chatapp-api/repositories/user_block"
 )
 
+// presenceSweepInterval is how often expired presence (e.g. after a crash) is cleaned up
+const presenceSweepInterval = 30 * time.Second
+
 // Hub manages all active websocket connections
 type Hub struct {
 	// Registered clients, key = userID
@@ -25,20 +33,32 @@ type Hub struct {
 	// Mutex to protect clients map
 	mu sync.RWMutex
 
-	// Repository to get conversation participants (for typing indicator)
+	// Repository to get conversation participants (for typing indicator and presence fan-out)
 	conversationRepo conversationRepo.ConversationRepository
 
-	// Repository to update online status in DB
+	// Repository to read presence settings and save last_seen in DB
 	userRepo userRepo.UserRepository
 
+	// Repository for live presence (Redis, expires without heartbeat)
+	presenceRepo presenceRepo.PresenceRepository
+
 	// Repository to update message receipts (delivered/read)
 	receiptRepo receiptRepo.MessageReceiptRepository
+
+	// Repository to check blocks (presence/typing is not sent between blocked users)
+	userBlockRepo userBlockRepo.UserBlockRepository
+
+	// Repository to find contacts (presence fan-out and "contacts" presence visibility)
+	contactRepo contactRepo.ContactRepository
 }
 
 // NewHub creates a new Hub instance
 func NewHub(conversationRepo conversationRepo.ConversationRepository, 
 	userRepo userRepo.UserRepository,
+	presenceRepo presenceRepo.PresenceRepository,
 	receiptRepo receiptRepo.MessageReceiptRepository,
+	userBlockRepo userBlockRepo.UserBlockRepository,
+	contactRepo contactRepo.ContactRepository,
 ) *Hub {
 	return &Hub{
 		clients: make(map[string]*Client),
@@ -46,12 +66,18 @@ func NewHub(conversationRepo conversationRepo.ConversationRepository,
 		unregister: make(chan *Client),
 		conversationRepo: conversationRepo,
 		userRepo: userRepo,
+		presenceRepo: presenceRepo,
 		receiptRepo: receiptRepo,
+		userBlockRepo: userBlockRepo,
+		contactRepo: contactRepo,
 	}
 }
 
 // Run starts the hub's main loop
 func (hub *Hub) Run() {
+	// Clean up presence left behind by crashed connections/instances
+	go hub.sweepPresence()
+
 	for {
 		select {
 		case client := <- hub.register:
@@ -60,30 +86,23 @@ func (hub *Hub) Run() {
 			hub.mu.Unlock()
 			log.Printf("User %s connected via WebSocket", client.userID)
 
-			// Update online status in DB
-			if err := hub.userRepo.UpdateOnlineStatus(context.Background(), client.userID, true); err != nil {
-				log.Printf("Failed to update online status for user %s: %v", client.userID, err)
-			}
-
-			// Broadcast to all online user that this user is now online
-			hub.broadcastOnlineStatus(client.userID, EventUserOnline)
+			// Save presence in Redis and tell peers that this user is now online (or dnd)
+			// Runs in the background so a slow Redis/DB doesn't block other registrations
+			go hub.updatePresence(client.userID, EventUserOnline)
 
 		case client := <- hub.unregister:
 			hub.mu.Lock()
-			if _, ok := hub.clients[client.userID]; ok {
+			// Only remove the client if it wasn't replaced by a newer connection of the same user
+			if current, ok := hub.clients[client.userID]; ok && current == client {
 				delete(hub.clients, client.userID)
 				close(client.send)
 			}
 			hub.mu.Unlock()
 			log.Printf("User %s disconnected from WebSocket", client.userID)
 		
-			// Update database: user offline + save last_seen (automatic in repo)
-			if err := hub.userRepo.UpdateOnlineStatus(context.Background(), client.userID, false); err != nil {
-				log.Printf("Failed to update offline status for user %s: %v", client.userID, err)
-			}
-
-			// Broadcast to all online user that this user is now offline or disconnected
-			hub.broadcastOnlineStatus(client.userID, EventUserOffline)
+			// Remove presence from Redis, save last_seen (in the background)
+			// and tell peers that this user is now offline or disconnected
+			go hub.updatePresence(client.userID, EventUserOffline)
 		}
 	}
 }
@@ -145,47 +164,213 @@ func (hub *Hub) HandleTypingEvent(senderID, conversationID, event string) {
 		return
 	}
 
-	// 4. Send to all participants except the sender
+	// 4. Send to all participants except the sender, users blocked either way
+	// and users who haven't accepted the DM yet (message request)
 	// User A doesn't need to see their own typing indicator
+	blocked := hub.blockedUserIDs(senderID)
 	for _, participant := range conv.Participants {
-		if participant.UserID != senderID {
+		if participant.UserID != senderID && !blocked[participant.UserID] && !participant.IsMessageRequest {
 			hub.SendToUser(participant.UserID, jsonData)
 		}
 	}
 }
 
-// broadcastOnlineStatus sends online/offline status to all connected users
-func (hub *Hub) broadcastOnlineStatus(userID, event string) {
-	// 1. Create message in WSMessage format
+// RefreshPresence recomputes a user's presence state, saves it in Redis and sends a presence_update to their peers
+// Called after idle/active transitions and when the user changes DND or custom status
+func (hub *Hub) RefreshPresence(userID string) {
+	hub.updatePresence(userID, EventPresenceUpdate)
+}
+
+// HandlePresenceUpdateEvent processes a "presence_update" event from a client (DND and custom status)
+func (hub *Hub) HandlePresenceUpdateEvent(userID string, data PresenceUpdateData) {
+	// 1. Validate the update
+	update := &domain.PresenceUpdate{
+		Status: data.Status,
+		StatusText: data.StatusText,
+		StatusEmoji: data.StatusEmoji,
+		ExpiresInMinutes: data.ExpiresInMinutes,
+		ClearStatus: data.ClearStatus,
+	}
+	if err := update.Validate(); err != nil {
+		log.Printf("Invalid presence_update from user %s: %v", userID, err)
+		return
+	}
+
+	// 2. Apply it to the user's settings and save
+	user, err := hub.userRepo.FindByID(context.Background(), userID)
+	if err != nil {
+		log.Printf("Failed to find user %s: %v", userID, err)
+		return
+	}
+
+	user.ApplyPresenceUpdate(update, time.Now())
+	if err := hub.userRepo.UpdatePresenceSettings(context.Background(), user); err != nil {
+		log.Printf("Failed to update presence settings for user %s: %v", userID, err)
+		return
+	}
+
+	// 3. Recompute state and tell peers
+	hub.RefreshPresence(userID)
+}
+
+// updatePresence works out the user's presence state from their connection, saves it and broadcasts it
+func (hub *Hub) updatePresence(userID, event string) {
+	ctx := context.Background()
+
+	// 1. Load user (DND flag, custom status and presence visibility)
+	user, err := hub.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to find user %s: %v", userID, err)
+		return
+	}
+
+	// 2. Resolve state: offline / dnd / idle / online
+	client, connected := hub.GetClient(userID)
+	status := domain.ResolvePresence(connected, user.DoNotDisturb, connected && client.isIdle())
+
+	// Skip stale connect/disconnect updates (the user disconnected or reconnected in the meantime)
+	if (event == EventUserOnline && status == domain.PresenceOffline) ||
+		(event == EventUserOffline && status != domain.PresenceOffline) {
+		return
+	}
+
+	// 3. Save live presence in Redis, and last_seen in DB when the user goes offline
+	if status == domain.PresenceOffline {
+		if err := hub.presenceRepo.Remove(ctx, userID); err != nil {
+			log.Printf("Failed to remove presence of user %s: %v", userID, err)
+		}
+
+		if event == EventUserOffline {
+			now := time.Now()
+			if err := hub.userRepo.UpdateLastSeen(ctx, userID, now); err != nil {
+				log.Printf("Failed to update last seen of user %s: %v", userID, err)
+			}
+			user.LastSeen = &now
+		}
+	} else if err := hub.presenceRepo.SetStatus(ctx, userID, status, presenceTTL); err != nil {
+		log.Printf("Failed to save presence of user %s: %v", userID, err)
+	}
+
+	user.PresenceStatus = status
+	user.IsOnline = status != domain.PresenceOffline
+
+	// 4. Tell peers
+	hub.broadcastPresence(user, event)
+}
+
+// heartbeat extends the presence of a connected user (called on every pong)
+func (hub *Hub) heartbeat(userID string) {
+	alive, err := hub.presenceRepo.Heartbeat(context.Background(), userID, presenceTTL)
+	if err != nil {
+		log.Printf("Failed to refresh presence of user %s: %v", userID, err)
+		return
+	}
+
+	// Presence expired (e.g. swept while Redis was unreachable): save it again
+	if !alive {
+		go hub.RefreshPresence(userID)
+	}
+}
+
+// sweepPresence periodically finds users whose presence expired without a disconnect
+// (e.g. the server crashed), saves their last_seen and tells their peers they are offline
+func (hub *Hub) sweepPresence() {
+	ticker := time.NewTicker(presenceSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := hub.presenceRepo.PopExpired(context.Background())
+		if err != nil {
+			log.Printf("Failed to sweep expired presence: %v", err)
+			continue
+		}
+
+		for _, userID := range expired {
+			// Still connected to this server: the heartbeat was just late, save presence again
+			if _, ok := hub.GetClient(userID); ok {
+				hub.RefreshPresence(userID)
+				continue
+			}
+
+			hub.updatePresence(userID, EventUserOffline)
+		}
+	}
+}
+
+// broadcastPresence sends a user's presence to connected users who share a conversation with them or are their contacts
+func (hub *Hub) broadcastPresence(user *domain.User, event string) {
+	// 1. Respect the user's presence setting ("nobody" = never announced)
+	if user.PresenceVisibility == domain.PresenceVisibilityNobody {
+		return
+	}
+
+	// 2. Find who may see it: contacts, plus users who share a conversation unless it is limited to contacts
+	recipientIDs, err := hub.contactRepo.FindContactIDs(context.Background(), user.ID)
+	if err != nil {
+		log.Printf("Failed to find contacts of user %s: %v", user.ID, err)
+		return
+	}
+
+	if user.PresenceVisibility != domain.PresenceVisibilityContacts {
+		peerIDs, err := hub.conversationRepo.FindPeerUserIDs(context.Background(), user.ID)
+		if err != nil {
+			log.Printf("Failed to find peers of user %s: %v", user.ID, err)
+			return
+		}
+		recipientIDs = append(recipientIDs, peerIDs...)
+	}
+
+	// 3. Create message in WSMessage format
+	statusText, statusEmoji := user.ActiveCustomStatus(time.Now())
+	var statusExpiresAt *time.Time
+	if statusText != nil || statusEmoji != nil {
+		statusExpiresAt = user.StatusExpiresAt
+	}
+
 	wsMessage := WSMessage{
-		Event: event, // "user_online" or "user_offline"
+		Event: event, // "user_online", "user_offline" or "presence_update"
 		Data: OnlineStatusData{
-			UserID: userID, // Which user changed status
+			UserID: user.ID, // Which user changed status
+			Status: user.PresenceStatus,
+			StatusText: statusText,
+			StatusEmoji: statusEmoji,
+			StatusExpiresAt: statusExpiresAt,
 		},
 	}
 
-	// 2. Convert to JSON bytes
+	// 4. Convert to JSON bytes
 	jsonData, err := json.Marshal(wsMessage)
 	if err != nil {
-		log.Printf("Failed to marshal online status: %v", err)
+		log.Printf("Failed to marshal presence: %v", err)
 		return
 	}
 
-	// 3. Send to all online user (except the user who changed status)
-	hub.mu.RLock()
-	defer hub.mu.RUnlock()
-
-	for id, client := range hub.clients {
-		if id != userID {
-			select {
-			case client.send <- jsonData:
-			default:
-				log.Printf("Failed to send online status to user %s: channel full", id)
-			}
+	// 5. Send to recipients that are online, once each (except users blocked either way)
+	skip := hub.blockedUserIDs(user.ID)
+	for _, recipientID := range recipientIDs {
+		if !skip[recipientID] {
+			skip[recipientID] = true
+			hub.SendToUser(recipientID, jsonData)
 		}
 	}
 }
 
+// blockedUserIDs returns the users that blocked or were blocked by userID
+func (hub *Hub) blockedUserIDs(userID string) map[string]bool {
+	blocked := make(map[string]bool)
+
+	userIDs, err := hub.userBlockRepo.FindRelatedUserIDs(context.Background(), userID)
+	if err != nil {
+		log.Printf("Failed to find blocks for user %s: %v", userID, err)
+		return blocked
+	}
+
+	for _, id := range userIDs {
+		blocked[id] = true
+	}
+	return blocked
+}
+
 // HandleMessageReadEvent processes a "message_read" event from a client
 // Updates the receipt status in DB and notifies other participants
 func (hub *Hub) HandleMessageReadEvent(readerID, conversationID, messageID string) {
@@ -1,17 +1,38 @@
 package websocket
 
+import "time"
+
 // Event types for WebSocket communication
 const (
 	// Server to client events
 	EventNewMessage = "new_message"
 	EventUserOnline = "user_online"
 	EventUserOffline = "user_offline"
+	EventMessageDeleted = "message_deleted"
+	EventMention = "mention" // Sent to users mentioned in a new (or edited) message
+
+	// Conversation lifecycle events (server to client)
+	EventConversationCreated = "conversation_created" // Sent to users who gain access to a conversation (created or added)
+	EventConversationUpdated = "conversation_updated"
+	EventParticipantAdded = "participant_added"
+	EventParticipantRemoved = "participant_removed"
+	EventRemovedFromConversation = "removed_from_conversation"
+	EventJoinRequestCreated = "join_request_created" // Sent to owners/admins of the group
+	EventMessageRequest = "message_request" // Sent to the recipient of a DM from a non-contact (instead of conversation_created)
+
+	// Contact events (server to client)
+	EventContactRequest = "contact_request" // Sent to the addressee of a new contact request
+	EventContactAccepted = "contact_accepted" // Sent to the requester when their request is accepted
 
 	// Client to server events (and forwarded to other clients)
 	EventTypingStart = "typing_start"
 	EventTypingStop = "typing_stop"
 	EventMessageRead = "message_read"
 	EventMessageDelivered = "message_delivered"
+
+	// Presence changes: client sends it to set DND/custom status,
+	// server sends it to peers when a user's state or custom status changes
+	EventPresenceUpdate = "presence_update"
 )
 
 // WSMessage is the standard WebSocket message format
@@ -28,9 +49,29 @@ type TypingData struct {
 	DisplayName string `json:"display_name"`
 }
 
-// OnlineStatusData is the payload for online/offline events
+// OnlineStatusData is the payload for online/offline and presence_update events
 type OnlineStatusData struct {
 	UserID string `json:"user_id"`
+	Status string `json:"status"` // "online", "idle", "dnd" or "offline"
+	StatusText *string `json:"status_text,omitempty"`
+	StatusEmoji *string `json:"status_emoji,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+}
+
+// PresenceUpdateData is the payload of a presence_update event sent by a client
+type PresenceUpdateData struct {
+	Status *string `json:"status,omitempty"` // "online" or "dnd"
+	StatusText *string `json:"status_text,omitempty"`
+	StatusEmoji *string `json:"status_emoji,omitempty"`
+	ExpiresInMinutes *int `json:"expires_in_minutes,omitempty"`
+	ClearStatus bool `json:"clear_status"`
+}
+
+// MentionData is the payload for mention events
+type MentionData struct {
+	MessageID string `json:"message_id"`
+	SenderID string `json:"sender_id"`
+	Type string `json:"type"` // "user", "all" or "here"
 }
 
 // MessageReadData is the payload for message read events
@@ -38,3 +79,16 @@ type MessageReadData struct {
 	UserID string `json:"user_id"`
 	MessageID string `json:"message_id"`
 }
+
+// ParticipantAddedData is the payload for participant_added events
+type ParticipantAddedData struct {
+	UserIDs []string `json:"user_ids"`
+	ActorID string `json:"actor_id"`
+}
+
+// ParticipantRemovedData is the payload for participant_removed and removed_from_conversation events
+type ParticipantRemovedData struct {
+	UserID string `json:"user_id"`
+	ActorID string `json:"actor_id"`
+	Reason string `json:"reason"` // "left", "kicked" or "banned"
+}